@@ -0,0 +1,184 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+#########################################################################
+# Weighted Addresses Section - Decorator selecting among weighted addresses
+#########################################################################
+*/
+
+// WeightedAddress is a single target parsed from the ADDRESSES specification
+type WeightedAddress struct {
+	Address string
+	Weight  int
+}
+
+// AddressesConfig is a decorator that reads a weighted list of addresses, e.g.
+// ADDRESSES=a:1=3,b:2=1, and selects among them for simple client side load balancing.
+// Read the selected address through GetAddress when the config may be reloaded concurrently
+type AddressesConfig struct {
+	Configurer
+	Addresses []WeightedAddress
+	Address   string
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewAddressesConfig creates a new AddressesConfig struct that decorates the next Configurer
+func NewAddressesConfig(next Configurer, opts ...Option) *AddressesConfig {
+	o := applyOptions(opts)
+	return &AddressesConfig{
+		Configurer: next,
+		source:     o.source,
+	}
+}
+
+// ParseWeightedAddresses parses a comma separated list of address=weight entries,
+// the weight is split on the last '=' so addresses may contain ':' for a port
+func ParseWeightedAddresses(spec string) ([]WeightedAddress, error) {
+	var addresses []WeightedAddress
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid weighted address %q: expected address=weight", entry)
+		}
+		weight, err := strconv.Atoi(entry[i+1:])
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid weight in %q: must be a positive integer", entry)
+		}
+		addresses = append(addresses, WeightedAddress{Address: entry[:i], Weight: weight})
+	}
+	return addresses, nil
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface
+func (a *AddressesConfig) Reload() error {
+	before := a.hooks.before(a)
+	err := a.reload()
+	a.hooks.notify(before, a, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (a *AddressesConfig) reload() error {
+	logf(a.Logger, "Reloading weighted addresses")
+	a.reloaded()
+
+	// Reload the base configuration
+	err := a.Configurer.Reload()
+
+	// Only take this layer's lock once the wrapped configuration has finished reloading
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Load the value from the source, the default only applies when the key is not set
+	spec := lookup(a.source, "ADDRESSES", "http://localhost=1")
+
+	addresses, parseErr := ParseWeightedAddresses(spec)
+	if parseErr != nil {
+		return errors.Join(err, layerError(a, parseErr))
+	}
+	// Select one target when the addresses change and keep it across reloads that change nothing,
+	// callers wanting per-call selection use PickAddress
+	if !slices.Equal(addresses, a.Addresses) {
+		a.Addresses = addresses
+		a.Address = a.pickAddress()
+	}
+	return err
+}
+
+// GetAddress returns the address selected by the last reload that changed the addresses and is safe to call while the config is reloading
+func (a *AddressesConfig) GetAddress() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Address
+}
+
+// PickAddress selects one of the addresses at random in proportion to its weight
+func (a *AddressesConfig) PickAddress() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.pickAddress()
+}
+
+// pickAddress selects a weighted address, the caller must hold the lock
+func (a *AddressesConfig) pickAddress() string {
+	total := 0
+	for _, target := range a.Addresses {
+		total += target.Weight
+	}
+	if total == 0 {
+		return ""
+	}
+
+	n := rand.IntN(total)
+	for _, target := range a.Addresses {
+		if n < target.Weight {
+			return target.Address
+		}
+		n -= target.Weight
+	}
+	return ""
+}
+
+// Clone returns a copy of the AddressesConfig and the chain it wraps and implements the Cloner interface
+func (a *AddressesConfig) Clone() Configurer {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	clone := &AddressesConfig{
+		Configurer: cloneConfigurer(a.Configurer),
+		Addresses:  slices.Clone(a.Addresses),
+		Address:    a.Address,
+		Logger:     a.Logger,
+		source:     a.source,
+	}
+	a.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// fields returns the current weighted addresses in the ADDRESSES format and the selected Address
+// as SelectedAddress, apart from Config.Address. It has no key since it is picked rather than loaded
+func (a *AddressesConfig) fields() []fieldValue {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	entries := make([]string, len(a.Addresses))
+	for i, target := range a.Addresses {
+		entries[i] = target.Address + "=" + strconv.Itoa(target.Weight)
+	}
+	return []fieldValue{
+		{"Addresses", "ADDRESSES", strings.Join(entries, ",")},
+		{"SelectedAddress", "", a.Address},
+	}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (a *AddressesConfig) replaceSource(replace func(Source) Source) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.source = replace(a.source)
+}
+
+// layerName returns the name the AddressesConfig reports its errors under
+func (a *AddressesConfig) layerName() string {
+	return "weighted addresses"
+}
+
+// Unwrap returns the Configurer wrapped by the AddressesConfig decorator
+func (a *AddressesConfig) Unwrap() Configurer {
+	return a.Configurer
+}
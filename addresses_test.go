@@ -0,0 +1,87 @@
+package configdecorator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseWeightedAddresses(t *testing.T) {
+	got, err := ParseWeightedAddresses("a:1=3, b:2=1")
+	if err != nil {
+		t.Fatalf("ParseWeightedAddresses() error = %v", err)
+	}
+	want := []WeightedAddress{{Address: "a:1", Weight: 3}, {Address: "b:2", Weight: 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseWeightedAddresses() = %v, want %v", got, want)
+	}
+}
+
+func TestParseWeightedAddressesMalformed(t *testing.T) {
+	for _, spec := range []string{"", "a:1", "=3", "a:1=0", "a:1=-2", "a:1=x", "a:1=3,,b=1"} {
+		if _, err := ParseWeightedAddresses(spec); err == nil {
+			t.Errorf("ParseWeightedAddresses(%q) error = nil, want an error", spec)
+		}
+	}
+}
+
+func TestAddressesConfigWeightDistribution(t *testing.T) {
+	a := NewAddressesConfig(&countingConfig{}, WithSource(MapSource{"ADDRESSES": "a:1=3,b:2=1"}))
+	if err := a.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	const picks = 10000
+	counts := make(map[string]int)
+	for range picks {
+		counts[a.PickAddress()]++
+	}
+	if len(counts) != 2 {
+		t.Fatalf("picked %v, want only a:1 and b:2", counts)
+	}
+	// a:1 carries three quarters of the weight, the bounds are many standard deviations wide
+	if share := float64(counts["a:1"]) / picks; share < 0.7 || share > 0.8 {
+		t.Errorf("a:1 share = %.3f, want about 0.75", share)
+	}
+}
+
+func TestAddressesConfigMalformedSpecKeepsAddresses(t *testing.T) {
+	src := MapSource{"ADDRESSES": "a:1=1"}
+	a := NewAddressesConfig(&countingConfig{}, WithSource(src))
+	if err := a.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	src["ADDRESSES"] = "a:1=oops"
+	if err := a.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the malformed spec to fail")
+	}
+	if got := a.GetAddress(); got != "a:1" {
+		t.Errorf("Address = %q, want the last good address", got)
+	}
+}
+
+func TestAddressesConfigKeepsSelectionWhileUnchanged(t *testing.T) {
+	src := MapSource{"ADDRESSES": "a:1=1,b:2=1,c:3=1"}
+	a := NewAddressesConfig(&countingConfig{}, WithSource(src))
+	if err := a.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	before := snapshot(a)
+	for range 50 {
+		if err := a.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if changes := diffSnapshots(before, snapshot(a)); len(changes) != 0 {
+			t.Fatalf("Reload() of unchanged addresses changed %v", changes)
+		}
+	}
+
+	src["ADDRESSES"] = "d:4=1"
+	if err := a.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := a.GetAddress(); got != "d:4" {
+		t.Errorf("Address = %q, want d:4 picked from the new addresses", got)
+	}
+}
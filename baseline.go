@@ -0,0 +1,47 @@
+package configdecorator
+
+import "fmt"
+
+/*
+#########################################################################
+# Baseline Section - Compares the live config with a committed baseline
+#########################################################################
+*/
+
+// CompareToBaseline parses the baseline config file at path, in the same layout FileConfig reads,
+// and reports every field where the live chain c differs from it, keyed by field name. An empty
+// format is taken from the file extension as FileConfig does. Old is the live value and New the
+// baseline value. A live field missing from the baseline is reported with an empty New, and a key
+// only in the baseline, because no layer of the live chain loads it, is reported under the key
+// itself with an empty Old
+func CompareToBaseline(c Configurer, path, format string) (map[string]Change, error) {
+	data, err := readConfigFile(path, DefaultMaxFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+
+	baseline, err := parseConfigFile(data, path, configFileFormat(path, format))
+	if err != nil {
+		return nil, err
+	}
+
+	drift := make(map[string]Change)
+	for _, field := range snapshot(c) {
+		// Derived fields are not loaded from a key so there is nothing to compare them with
+		if field.Key == "" {
+			continue
+		}
+		want, ok := baseline[field.Key]
+		delete(baseline, field.Key)
+		if !ok || want != field.Value {
+			drift[field.Field] = Change{Field: field.Field, Old: field.Value, New: want}
+		}
+	}
+
+	// Whatever is left in the baseline has no matching layer in the live chain, so it
+	// has no field name and is reported under its key
+	for key, want := range baseline {
+		drift[key] = Change{Field: key, New: want}
+	}
+	return drift, nil
+}
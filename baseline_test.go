@@ -0,0 +1,76 @@
+package configdecorator
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+)
+
+// baselineChain returns a reloaded Database over Config chain reading src
+func baselineChain(t *testing.T, src MapSource) Configurer {
+	t.Helper()
+	chain := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	return chain
+}
+
+func TestCompareToBaselineMatching(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "baseline.json",
+		`{"ADDRESS": "http://localhost", "PORT": "9000", "DB_ADDRESS": "http://db", "DB_PORT": "37017"}`)
+	chain := baselineChain(t, MapSource{"PORT": "9000", "DB_ADDRESS": "http://db"})
+
+	drift, err := CompareToBaseline(chain, path, "json")
+	if err != nil {
+		t.Fatalf("CompareToBaseline() error = %v", err)
+	}
+	if len(drift) != 0 {
+		t.Errorf("CompareToBaseline() = %v, want no drift", drift)
+	}
+}
+
+func TestCompareToBaselineDrifted(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "baseline.json",
+		`{"ADDRESS": "http://localhost", "PORT": "9000", "DB_ADDRESS": "http://db", "MOTD": "hi"}`)
+	chain := baselineChain(t, MapSource{"PORT": "9001", "DB_ADDRESS": "http://db"})
+
+	drift, err := CompareToBaseline(chain, path, "json")
+	if err != nil {
+		t.Fatalf("CompareToBaseline() error = %v", err)
+	}
+	want := map[string]Change{
+		"Port":   {Field: "Port", Old: "9001", New: "9000"},
+		"DBPort": {Field: "DBPort", Old: "37017"},
+		"MOTD":   {Field: "MOTD", New: "hi"},
+	}
+	if !maps.Equal(drift, want) {
+		t.Errorf("CompareToBaseline() = %v, want %v", drift, want)
+	}
+}
+
+func TestCompareToBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+	chain := baselineChain(t, MapSource{})
+	if _, err := CompareToBaseline(chain, filepath.Join(dir, "missing.json"), "json"); err == nil {
+		t.Error("CompareToBaseline() error = nil, want an error for a missing baseline")
+	}
+	path := writeFile(t, dir, "broken.json", `{"PORT":`)
+	if _, err := CompareToBaseline(chain, path, "json"); err == nil {
+		t.Error("CompareToBaseline() error = nil, want an error for a malformed baseline")
+	}
+}
+
+func TestCompareToBaselineFormatFromExtension(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "baseline.ini", "port = 9001\n")
+	chain := baselineChain(t, MapSource{"PORT": "9000"})
+
+	drift, err := CompareToBaseline(chain, path, "")
+	if err != nil {
+		t.Fatalf("CompareToBaseline() error = %v, want the format taken from the extension", err)
+	}
+	want := Change{Field: "Port", Old: "9000", New: "9001"}
+	if got := drift["Port"]; got != want {
+		t.Errorf("Port drift = %v, want %v", got, want)
+	}
+}
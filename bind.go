@@ -0,0 +1,97 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+#########################################################################
+# Bind Section - Struct tag driven loading of config fields
+#########################################################################
+*/
+
+// Bind loads the fields of the struct pointed to by target from the environment based on
+// their struct tags, see BindSource for the supported tags and field types
+func Bind(target any) error {
+	return BindSource(target, EnvSource{})
+}
+
+// BindSource loads the fields of the struct pointed to by target from source based on their
+// struct tags, e.g.
+//
+//	Address string `env:"ADDRESS" default:"http://localhost"`
+//
+// The env tag names the key to read and the default tag is used only when the key is not set.
+// String, int and bool fields are supported with bools parsed by ParseBool, a value that does not
+// parse as the field's type returns an error. Fields without an env tag, unexported fields and
+// embedded fields such as the wrapped Configurer of a decorator are skipped
+func BindSource(target any, source Source) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: target must be a non-nil pointer to a struct, got %T", target)
+	}
+	v = v.Elem()
+	source = sourceOrEnv(source)
+
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key, ok := field.Tag.Lookup("env")
+		if !ok || field.Anonymous || !field.IsExported() {
+			continue
+		}
+
+		raw, ok := source.Get(key)
+		if !ok {
+			raw, ok = field.Tag.Lookup("default")
+			if !ok {
+				// Leave the field untouched when there is neither a value nor a default
+				continue
+			}
+		}
+
+		if err := setField(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("binding %s from %s: %w", field.Name, key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseBool parses the common spellings of a boolean case insensitively, true, t, yes, y, on,
+// enabled and 1 are true and false, f, no, n, off, disabled and 0 are false. Anything else is an error
+func ParseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "true", "t", "yes", "y", "on", "enabled", "1":
+		return true, nil
+	case "false", "f", "no", "n", "off", "disabled", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q: expected true/false, yes/no, on/off, enabled/disabled or 1/0", raw)
+}
+
+// setField parses raw as the type of field and stores it
+func setField(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package configdecorator
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type bindTarget struct {
+	Configurer
+	Name    string `env:"NAME" default:"anon"`
+	Workers int    `env:"WORKERS" default:"4"`
+	Small   int8   `env:"SMALL"`
+	Debug   bool   `env:"DEBUG"`
+	Plain   string
+	hidden  string `env:"HIDDEN"`
+}
+
+func TestBindSourceDefaultsOnlyWhenUnset(t *testing.T) {
+	var target bindTarget
+	if err := BindSource(&target, MapSource{"NAME": "", "DEBUG": "yes"}); err != nil {
+		t.Fatalf("BindSource() error = %v", err)
+	}
+	if target.Name != "" {
+		t.Errorf("Name = %q, want the set empty value over the default", target.Name)
+	}
+	if target.Workers != 4 {
+		t.Errorf("Workers = %d, want the default 4 for an unset key", target.Workers)
+	}
+	if !target.Debug {
+		t.Error("Debug = false, want yes to parse as true")
+	}
+
+	// A field with neither a value nor a default is left untouched
+	target.Small = 7
+	if err := BindSource(&target, MapSource{}); err != nil {
+		t.Fatalf("BindSource() error = %v", err)
+	}
+	if target.Small != 7 {
+		t.Errorf("Small = %d, want it left untouched", target.Small)
+	}
+}
+
+func TestBindSourceIntErrors(t *testing.T) {
+	var target bindTarget
+	err := BindSource(&target, MapSource{"WORKERS": "many", "SMALL": "300"})
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("BindSource() error = %v, want the WORKERS syntax error", err)
+	}
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("BindSource() error = %v, want the SMALL range error for an int8", err)
+	}
+}
+
+func TestBindSourceSkipsEmbeddedAndUnexported(t *testing.T) {
+	inner := &countingConfig{}
+	target := bindTarget{Configurer: inner, Plain: "kept"}
+	if err := BindSource(&target, MapSource{"HIDDEN": "x", "PLAIN": "y"}); err != nil {
+		t.Fatalf("BindSource() error = %v", err)
+	}
+	if target.Configurer != inner {
+		t.Error("BindSource() replaced the embedded Configurer")
+	}
+	if target.hidden != "" || target.Plain != "kept" {
+		t.Errorf("hidden, Plain = %q, %q, want unexported and untagged fields skipped", target.hidden, target.Plain)
+	}
+}
+
+func TestBindSourceRejectsNonStruct(t *testing.T) {
+	var n int
+	for _, target := range []any{nil, bindTarget{}, &n, (*bindTarget)(nil)} {
+		if err := BindSource(target, MapSource{}); err == nil {
+			t.Errorf("BindSource(%T) error = nil, want an error", target)
+		}
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	for _, tt := range []struct {
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{raw: "true", want: true},
+		{raw: "t", want: true},
+		{raw: "yes", want: true},
+		{raw: "y", want: true},
+		{raw: "on", want: true},
+		{raw: "enabled", want: true},
+		{raw: "1", want: true},
+		{raw: "TRUE", want: true},
+		{raw: "Yes", want: true},
+		{raw: "false"},
+		{raw: "f"},
+		{raw: "no"},
+		{raw: "n"},
+		{raw: "off"},
+		{raw: "disabled"},
+		{raw: "0"},
+		{raw: "OFF"},
+		{raw: "maybe", wantErr: true},
+		{raw: "", wantErr: true},
+		{raw: " true", wantErr: true},
+	} {
+		got, err := ParseBool(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBool(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBool(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package configdecorator
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+/*
+#########################################################################
+# Change Notification Section - Callbacks fired when a reload changes fields
+#########################################################################
+*/
+
+// Change describes a single field whose value was changed by a reload. Callbacks registered with
+// OnChange receive every change made by a reload of the layer they were registered on, including
+// fields of the layers it wraps, so registering on the outermost decorator sees every change.
+// Callbacks run in ascending priority order, OnChange registers with priority 0 and callbacks of
+// equal priority run in registration order. They do not run on the first load, when nothing changed or
+// when the reload failed, the next successful reload reports the changes made since the last one
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// fieldValue is the current value of a named config field and the key it is loaded from,
+// the key is empty for a field that is derived from other values rather than loaded
+type fieldValue struct {
+	Field string
+	Key   string
+	Value string
+}
+
+// snapshot reads the current value of every known field of the chain below c, ordered from the
+// outermost layer inwards. Field names are the Go field names, e.g. "DBAddress"
+func snapshot(c Configurer) []fieldValue {
+	var values []fieldValue
+	for c != nil {
+		values = append(values, layerFields(c)...)
+
+		u, ok := c.(Unwrapper)
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+	return values
+}
+
+// fieldLayer is implemented by the layers that hold config fields so snapshot can read them
+type fieldLayer interface {
+	// fields reads the current value of the layer's own fields, not of the layers it wraps
+	fields() []fieldValue
+}
+
+// layerFields reads the current value of the fields of c itself, nil when c holds no fields
+func layerFields(c Configurer) []fieldValue {
+	if layer, ok := c.(fieldLayer); ok {
+		return layer.fields()
+	}
+	return nil
+}
+
+// diffSnapshots returns a Change for every field whose value differs between before and after,
+// both snapshots must be taken from the same chain
+func diffSnapshots(before, after []fieldValue) []Change {
+	var changes []Change
+	for i := range min(len(before), len(after)) {
+		if before[i].Value != after[i].Value {
+			changes = append(changes, Change{Field: after[i].Field, Old: before[i].Value, New: after[i].Value})
+		}
+	}
+	return changes
+}
+
+// changeCallback is a registered OnChange callback, its priority and the id it can be removed by
+type changeCallback struct {
+	id       int
+	priority int
+	fn       func([]Change)
+}
+
+// changeHooks holds the OnChange callbacks of a config layer, ordered by priority
+type changeHooks struct {
+	mu        sync.Mutex
+	callbacks []changeCallback
+	nextID    int
+	loaded    bool
+	muted     bool
+	// pending is the snapshot from before the first of a run of failed reloads
+	pending []fieldValue
+}
+
+// changeNotifier is embedded by the config layers to give them OnChange, OnChangeWithPriority and
+// SubscribeFields, each layer wraps its Reload with hooks.before and hooks.notify
+type changeNotifier struct {
+	hooks changeHooks
+}
+
+// OnChange registers fn to run after a successful reload changes a field of the layer or the chain it wraps
+func (n *changeNotifier) OnChange(fn func([]Change)) {
+	n.hooks.add(0, fn)
+}
+
+// OnChangeWithPriority registers fn like OnChange, callbacks with a lower priority run first
+func (n *changeNotifier) OnChangeWithPriority(priority int, fn func([]Change)) {
+	n.hooks.add(priority, fn)
+}
+
+// SubscribeFields returns a channel receiving the changes a reload makes to fields, given by their
+// Go field names such as "DBAddress". Call unsubscribe to stop the subscription and close the channel
+func (n *changeNotifier) SubscribeFields(fields ...string) (changes <-chan map[string]Change, unsubscribe func()) {
+	return n.hooks.subscribe(fields)
+}
+
+// hookSet returns the OnChange callbacks so a Store can hold them back during a reload
+func (n *changeNotifier) hookSet() *changeHooks {
+	return &n.hooks
+}
+
+// hookedLayer is implemented by the layers that hold OnChange callbacks so a Store can hold
+// them back while it reloads a clone of the chain
+type hookedLayer interface {
+	hookSet() *changeHooks
+}
+
+// add registers fn to run after a reload that changes a field, callbacks run in ascending
+// priority order and callbacks with the same priority run in registration order. It returns
+// the id that removes the callback again
+func (h *changeHooks) add(priority int, fn func([]Change)) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	h.callbacks = append(h.callbacks, changeCallback{id: h.nextID, priority: priority, fn: fn})
+	slices.SortStableFunc(h.callbacks, func(a, b changeCallback) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+	return h.nextID
+}
+
+// remove unregisters the callback added with id
+func (h *changeHooks) remove(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks = slices.DeleteFunc(h.callbacks, func(cb changeCallback) bool {
+		return cb.id == id
+	})
+}
+
+// subscribe registers a callback that sends the changes touching fields, keyed by field name, on the
+// returned channel. The channel is buffered so a reload never waits on a slow subscriber, changes
+// that arrive before the previous ones were received are merged into them. Calling unsubscribe
+// removes the callback and closes the channel
+func (h *changeHooks) subscribe(fields []string) (<-chan map[string]Change, func()) {
+	ch := make(chan map[string]Change, 1)
+
+	// mu orders sends against closing the channel, a clone of the config may share the callback
+	var mu sync.Mutex
+	closed := false
+
+	id := h.add(0, func(changes []Change) {
+		matched := make(map[string]Change)
+		for _, change := range changes {
+			if slices.Contains(fields, change.Field) {
+				matched[change.Field] = change
+			}
+		}
+		if len(matched) == 0 {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+
+		// Fold in changes the subscriber has not received yet, keeping the oldest value
+		select {
+		case pending := <-ch:
+			for field, change := range matched {
+				if prev, ok := pending[field]; ok {
+					change.Old = prev.Old
+				}
+				pending[field] = change
+			}
+			matched = pending
+		default:
+		}
+		ch <- matched
+	})
+
+	unsubscribe := func() {
+		h.remove(id)
+		mu.Lock()
+		defer mu.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// before snapshots the chain below c ahead of a reload, it returns nil when no callback
+// would fire so a chain without callbacks does no extra work
+func (h *changeHooks) before(c Configurer) []fieldValue {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.loaded || h.muted || len(h.callbacks) == 0 {
+		return nil
+	}
+	return snapshot(c)
+}
+
+// notify compares the chain below c with the snapshot taken before the reload and runs every
+// callback, in priority order, when a field changed. Nothing fires on the first load or when the
+// reload failed with err, the next successful reload reports the changes since the last one that did
+func (h *changeHooks) notify(before []fieldValue, c Configurer, err error) {
+	h.mu.Lock()
+	h.loaded = true
+	if err != nil {
+		if h.pending == nil {
+			h.pending = before
+		}
+		h.mu.Unlock()
+		return
+	}
+	if h.pending != nil {
+		before, h.pending = h.pending, nil
+	}
+	callbacks := slices.Clone(h.callbacks)
+	h.mu.Unlock()
+
+	if before == nil {
+		return
+	}
+	runCallbacks(callbacks, diffSnapshots(before, snapshot(c)))
+}
+
+// runCallbacks runs every callback, in priority order, when changes is not empty
+func runCallbacks(callbacks []changeCallback, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	for _, cb := range callbacks {
+		cb.fn(changes)
+	}
+}
+
+// muteHooks holds back or releases the callbacks of every layer of the chain below c,
+// a muted layer still records that it was loaded but runs no callback
+func muteHooks(c Configurer, muted bool) {
+	for c != nil {
+		if layer, ok := c.(hookedLayer); ok {
+			h := layer.hookSet()
+			h.mu.Lock()
+			h.muted = muted
+			h.mu.Unlock()
+		}
+
+		u, ok := c.(Unwrapper)
+		if !ok {
+			return
+		}
+		c = u.Unwrap()
+	}
+}
+
+// notifyChain runs the callbacks of every layer of after with the changes from the matching
+// layer of before, after must be a clone of before. Layers of before that were never loaded
+// stay quiet so the first load still fires nothing
+func notifyChain(before, after Configurer) {
+	for before != nil && after != nil {
+		if prev, ok := before.(hookedLayer); ok && prev.hookSet().wasLoaded() {
+			if layer, ok := after.(hookedLayer); ok {
+				layer.hookSet().notifyChanges(before, after)
+			}
+		}
+
+		bu, ok := before.(Unwrapper)
+		if !ok {
+			return
+		}
+		au, ok := after.(Unwrapper)
+		if !ok {
+			return
+		}
+		before, after = bu.Unwrap(), au.Unwrap()
+	}
+}
+
+// wasLoaded reports whether the layer holding h has been reloaded before
+func (h *changeHooks) wasLoaded() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.loaded
+}
+
+// notifyChanges runs every callback with the changes between the chains below before and after
+func (h *changeHooks) notifyChanges(before, after Configurer) {
+	h.mu.Lock()
+	callbacks := slices.Clone(h.callbacks)
+	h.mu.Unlock()
+
+	if len(callbacks) == 0 {
+		return
+	}
+	runCallbacks(callbacks, diffSnapshots(snapshot(before), snapshot(after)))
+}
+
+// copyTo copies the registered callbacks and load state into dst for a cloned config
+func (h *changeHooks) copyTo(dst *changeHooks) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	dst.callbacks = slices.Clone(h.callbacks)
+	dst.loaded = h.loaded
+}
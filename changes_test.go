@@ -0,0 +1,139 @@
+package configdecorator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSnapshotIncludesEveryFieldLayer(t *testing.T) {
+	src := MapSource{"MAX_WORKERS": "3", "ADDRESSES": "http://only=1", "LOG_LEVEL": "debug"}
+	resources := NewResourceAwareConfig(NewConfig("", "", WithSource(src)), WithSource(src))
+	chain := NewEnumField(NewAddressesConfig(resources, WithSource(src)), "LOG_LEVEL", "info", map[string]int{"debug": 0, "info": 1}, WithSource(src))
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	want := []fieldValue{
+		{"LOG_LEVEL", "LOG_LEVEL", "debug"},
+		{"Addresses", "ADDRESSES", "http://only=1"},
+		{"SelectedAddress", "", "http://only"},
+		{"MaxWorkers", "MAX_WORKERS", "3"},
+		{"Address", "ADDRESS", "http://localhost"},
+		{"Port", "PORT", "8081"},
+	}
+	if got := snapshot(chain); !slices.Equal(got, want) {
+		t.Errorf("snapshot() = %v, want %v", got, want)
+	}
+}
+
+func TestOnChangeRunsCallbacksByPriority(t *testing.T) {
+	src := MapSource{"PORT": "9000"}
+	c := NewConfig("", "", WithSource(src))
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	var order []string
+	record := func(name string) func([]Change) {
+		return func([]Change) { order = append(order, name) }
+	}
+	// Registered out of priority order, equal priorities keep their registration order
+	c.OnChangeWithPriority(10, record("late"))
+	c.OnChange(record("default first"))
+	c.OnChangeWithPriority(-5, record("early"))
+	c.OnChange(record("default second"))
+
+	src["PORT"] = "9001"
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	want := []string{"early", "default first", "default second", "late"}
+	if !slices.Equal(order, want) {
+		t.Errorf("callback order = %v, want %v", order, want)
+	}
+}
+
+func TestSubscribeFieldsOnlyOwnFields(t *testing.T) {
+	src := MapSource{"DB_ADDRESS": "http://db", "DB_PORT": "27017", "PORT": "9000"}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	if err := db.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	dbChanges, unsubscribeDB := db.SubscribeFields("DBAddress")
+	defer unsubscribeDB()
+	portChanges, unsubscribePort := db.SubscribeFields("Port")
+	defer unsubscribePort()
+
+	src["DB_ADDRESS"] = "http://db2"
+	src["DB_PORT"] = "27018"
+	if err := db.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	// A second change before the subscriber reads is merged, keeping the oldest value
+	src["DB_ADDRESS"] = "http://db3"
+	if err := db.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	select {
+	case got := <-dbChanges:
+		want := map[string]Change{"DBAddress": {Field: "DBAddress", Old: "http://db", New: "http://db3"}}
+		if len(got) != 1 || got["DBAddress"] != want["DBAddress"] {
+			t.Errorf("DBAddress subscriber got %v, want %v", got, want)
+		}
+	default:
+		t.Fatal("DBAddress subscriber received nothing")
+	}
+	select {
+	case got := <-portChanges:
+		t.Errorf("Port subscriber got %v, want nothing for other fields", got)
+	default:
+	}
+}
+
+func TestSubscribeFieldsUnsubscribeCloses(t *testing.T) {
+	src := MapSource{"PORT": "9000"}
+	c := NewConfig("", "", WithSource(src))
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	changes, unsubscribe := c.SubscribeFields("Port")
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-changes; ok {
+		t.Error("channel still open after unsubscribe")
+	}
+	// Reloads after unsubscribing no longer reach the closed channel
+	src["PORT"] = "9001"
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+}
+
+func TestOnChangeOnEveryDecorator(t *testing.T) {
+	src := MapSource{"PORT": "9000", "MAX_WORKERS": "2", "FEATURE_WORKERS": "on"}
+	base := NewConfig("", "", WithSource(src))
+	resources := NewResourceAwareConfig(base, WithSource(src))
+	g := NewGate(resources, "FEATURE_WORKERS", WithSource(src))
+	f := NewFencedConfig(g)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	var outer, gate []Change
+	f.OnChange(func(changes []Change) { outer = changes })
+	g.OnChange(func(changes []Change) { gate = changes })
+
+	src["PORT"], src["MAX_WORKERS"] = "9001", "4"
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	want := []Change{{Field: "MaxWorkers", Old: "2", New: "4"}, {Field: "Port", Old: "9000", New: "9001"}}
+	if !slices.Equal(outer, want) {
+		t.Errorf("FencedConfig changes = %v, want %v", outer, want)
+	}
+	if !slices.Equal(gate, want) {
+		t.Errorf("Gate changes = %v, want %v", gate, want)
+	}
+}
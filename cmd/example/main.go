@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lkendrickd/configdecorator"
+)
+
+// main function which is where the application will begin execution
+
+func main() {
+	// The configs are silent by default, log their reload messages to stdout for the example
+	logger := log.New(os.Stdout, "", 0)
+
+	// Create a new Config and DatabaseConfig note the current values before reloading
+	config := configdecorator.NewConfig("http://webapp", "8080")
+	dbConfig := configdecorator.NewDatabaseConfig(config, "http://mongodb", "27017")
+	motdConfig := configdecorator.NewMessageOfTheDay(dbConfig, "Hello, World!")
+	config.Logger = logger
+	dbConfig.Logger = logger
+	motdConfig.Logger = logger
+
+	// Print the current values
+	fmt.Printf("Config Address: %s, Port: %s\n", config.Address, config.Port)
+	fmt.Printf("Database Address: %s, Port: %s\n", dbConfig.DBAddress, dbConfig.DBPort)
+	fmt.Printf("Message of the Day: %s\n", motdConfig.MOTD)
+
+	// Reload the last decorator in the chain which will reload
+	// all the decorators.
+	if err := motdConfig.Reload(); err != nil {
+		fmt.Printf("Error reloading configuration: %v\n", err)
+		return
+	}
+
+	// Print the new values reloaded from the environment variables
+	fmt.Printf("Web App Address: %s, Port: %s\n", config.Address, config.Port)
+	fmt.Printf("Database Address: %s, Port: %s\n", dbConfig.DBAddress, dbConfig.DBPort)
+	fmt.Printf("Message of the Day: %s\n", motdConfig.MOTD)
+
+	/*
+		Additional Notes
+		The Config struct can be used as a standalone configuration struct
+		The DatabaseConfig struct can be used as a standalone configuration struct
+		The MessageOfTheDay struct can be used as a standalone configuration struct
+		The DatabaseConfig and MessageOfTheDay structs can be combined to create a configuration with both database and message of the day functionality
+		You could use the final struct that implements the Configurer interface to use in the context of an application.
+
+		Example:
+
+		type Service struct {
+			configdecorator.Configurer
+			*http.Server
+		}
+
+		You could then just call Service.Reload() to reload the configuration for the service thus reloading all the decorators in the chain.
+	*/
+
+}
@@ -0,0 +1,116 @@
+package configdecorator
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+/*
+#########################################################################
+# Base Config Section
+#########################################################################
+*/
+
+// Config is the base configuration struct for our application, read the fields through
+// the accessor methods when the config may be reloaded concurrently
+type Config struct {
+	Address string `env:"ADDRESS" default:"http://localhost" flag:"address" usage:"address to listen on"`
+	Port    string `env:"PORT" default:"8081" flag:"port" usage:"port to listen on"`
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface
+func (c *Config) Reload() error {
+	before := c.hooks.before(c)
+	err := c.reload()
+	c.hooks.notify(before, c, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (c *Config) reload() error {
+	logf(c.Logger, "Reloading base config")
+	c.reloaded()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Load the tagged fields from the source, defaults only apply when a key is not set
+	return layerError(c, BindSource(c, c.source))
+}
+
+// GetAddress returns the Address and is safe to call while the config is reloading
+func (c *Config) GetAddress() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Address
+}
+
+// GetPort returns the Port and is safe to call while the config is reloading
+func (c *Config) GetPort() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Port
+}
+
+// AddressCtx returns the ADDRESS override carried in ctx, falling back to the config Address
+func (c *Config) AddressCtx(ctx context.Context) string {
+	return requestOverride(ctx, "ADDRESS", c.GetAddress())
+}
+
+// PortCtx returns the PORT override carried in ctx, falling back to the config Port
+func (c *Config) PortCtx(ctx context.Context) string {
+	return requestOverride(ctx, "PORT", c.GetPort())
+}
+
+// Clone returns a copy of the Config and implements the Cloner interface
+func (c *Config) Clone() Configurer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	clone := &Config{
+		Address: c.Address,
+		Port:    c.Port,
+		Logger:  c.Logger,
+		source:  c.source,
+	}
+	c.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// NewConfig creates a new Config struct, pass WithSource to read from something other than the environment
+func NewConfig(address string, port string, opts ...Option) *Config {
+	o := applyOptions(opts)
+	return &Config{
+		Address: address,
+		Port:    port,
+		source:  o.source,
+	}
+}
+
+// fields returns the current Address and Port
+func (c *Config) fields() []fieldValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return []fieldValue{
+		{"Address", "ADDRESS", c.Address},
+		{"Port", "PORT", c.Port},
+	}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (c *Config) replaceSource(replace func(Source) Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = replace(c.source)
+}
+
+// layerName returns the name the Config reports its errors under
+func (c *Config) layerName() string {
+	return "base config"
+}
@@ -0,0 +1,39 @@
+// Package configdecorator implements reloadable application configuration with the
+// Decorator pattern. Each config layer implements Configurer and wraps the layer below
+// it, reloading the outermost layer reloads the whole chain
+package configdecorator
+
+import "log"
+
+// Configurer defines an interface that all concrete configs and decorators will implement
+// this interface will allow us to embed decorators in other decorators and reload the configuration
+type Configurer interface {
+	Reload() error
+}
+
+// logf writes a debug message to logger, configs are silent unless a logger is set
+func logf(logger *log.Logger, format string, args ...any) {
+	if logger != nil {
+		logger.Printf(format, args...)
+	}
+}
+
+// Cloner is implemented by configs that can produce an independent copy of themselves,
+// decorators clone the Configurer they wrap so the copy shares no state with the original
+type Cloner interface {
+	Clone() Configurer
+}
+
+// cloneConfigurer clones c when it implements Cloner and otherwise returns c unchanged
+func cloneConfigurer(c Configurer) Configurer {
+	if cl, ok := c.(Cloner); ok {
+		return cl.Clone()
+	}
+	return c
+}
+
+// Unwrapper is implemented by decorators that wrap another Configurer, it exposes the
+// wrapped layer so wrappers such as Gate can skip a layer and reload the one below it
+type Unwrapper interface {
+	Unwrap() Configurer
+}
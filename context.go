@@ -0,0 +1,31 @@
+package configdecorator
+
+import "context"
+
+/*
+#########################################################################
+# Request Overrides Section - Per-request overrides carried in a context
+#########################################################################
+*/
+
+// requestOverridesKey is the context key for request scoped overrides
+type requestOverridesKey struct{}
+
+// WithRequestOverrides returns a copy of ctx carrying overrides keyed by environment variable
+// name, e.g. "ADDRESS". The context aware accessors prefer these over the global config values
+func WithRequestOverrides(ctx context.Context, overrides map[string]string) context.Context {
+	copied := make(map[string]string, len(overrides))
+	for k, v := range overrides {
+		copied[k] = v
+	}
+	return context.WithValue(ctx, requestOverridesKey{}, copied)
+}
+
+// requestOverride returns the override for key carried in ctx, or value when there is none
+func requestOverride(ctx context.Context, key string, value string) string {
+	overrides, _ := ctx.Value(requestOverridesKey{}).(map[string]string)
+	if v, ok := overrides[key]; ok {
+		return v
+	}
+	return value
+}
@@ -0,0 +1,36 @@
+package configdecorator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestOverrides(t *testing.T) {
+	src := MapSource{"ADDRESS": "http://global", "PORT": "9000", "MOTD": "global"}
+	base := NewConfig("", "", WithSource(src))
+	motd := NewMessageOfTheDay(NewDatabaseConfig(base, "", "", WithSource(src)), "", WithSource(src))
+	if err := motd.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	overrides := map[string]string{"ADDRESS": "http://canary", "MOTD": ""}
+	ctx := WithRequestOverrides(context.Background(), overrides)
+	// Changing the map afterwards must not leak into the context
+	overrides["PORT"] = "1"
+
+	if got := base.AddressCtx(ctx); got != "http://canary" {
+		t.Errorf("AddressCtx() = %q, want the override", got)
+	}
+	if got := motd.MOTDCtx(ctx); got != "" {
+		t.Errorf("MOTDCtx() = %q, want the empty override", got)
+	}
+	if got := base.PortCtx(ctx); got != "9000" {
+		t.Errorf("PortCtx() = %q, want the global value without an override", got)
+	}
+	if got := base.AddressCtx(context.Background()); got != "http://global" {
+		t.Errorf("AddressCtx() without overrides = %q, want the global value", got)
+	}
+	if got := base.GetAddress(); got != "http://global" {
+		t.Errorf("GetAddress() = %q, want overrides to leave the global config untouched", got)
+	}
+}
@@ -0,0 +1,131 @@
+package configdecorator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+/*
+#########################################################################
+# Database Config Section - Decorator for the Config struct
+#########################################################################
+*/
+
+// DatabaseConfig is a decorator for the Config struct, read the fields through the
+// accessor methods when the config may be reloaded concurrently
+type DatabaseConfig struct {
+	Configurer
+	DBAddress string `env:"DB_ADDRESS" default:"http://localhost" flag:"db-address" usage:"address of the database"`
+	DBPort    string `env:"DB_PORT" default:"37017" flag:"db-port" usage:"port of the database"`
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewDatabaseConfig creates a new DatabaseConfig struct dependecy inject the Configurer interface
+// this will allow us to reload the base configuration when the DatabaseConfig is reloaded
+func NewDatabaseConfig(config Configurer, dbAddress string, dbPort string, opts ...Option) *DatabaseConfig {
+	o := applyOptions(opts)
+	return &DatabaseConfig{
+		Configurer: config,
+		DBAddress:  dbAddress,
+		DBPort:     dbPort,
+		source:     o.source,
+	}
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface.
+// The wrapped configuration is reloaded before this layer takes its own lock, so a decorator never holds its
+// lock while an inner layer locks and a chain cannot deadlock against itself
+func (d *DatabaseConfig) Reload() error {
+	before := d.hooks.before(d)
+	err := d.reload()
+	d.hooks.notify(before, d, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (d *DatabaseConfig) reload() error {
+	logf(d.Logger, "Reloading database config")
+	d.reloaded()
+
+	// Reload the base configuration
+	err := d.Configurer.Reload()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Load the tagged fields from the source, defaults only apply when a key is not set
+	return errors.Join(err, layerError(d, BindSource(d, d.source)))
+}
+
+// GetDBAddress returns the DBAddress and is safe to call while the config is reloading
+func (d *DatabaseConfig) GetDBAddress() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.DBAddress
+}
+
+// GetDBPort returns the DBPort and is safe to call while the config is reloading
+func (d *DatabaseConfig) GetDBPort() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.DBPort
+}
+
+// DBAddressCtx returns the DB_ADDRESS override carried in ctx, falling back to the config DBAddress
+func (d *DatabaseConfig) DBAddressCtx(ctx context.Context) string {
+	return requestOverride(ctx, "DB_ADDRESS", d.GetDBAddress())
+}
+
+// DBPortCtx returns the DB_PORT override carried in ctx, falling back to the config DBPort
+func (d *DatabaseConfig) DBPortCtx(ctx context.Context) string {
+	return requestOverride(ctx, "DB_PORT", d.GetDBPort())
+}
+
+// Clone returns a copy of the DatabaseConfig and the chain it wraps and implements the Cloner interface
+func (d *DatabaseConfig) Clone() Configurer {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	clone := &DatabaseConfig{
+		Configurer: cloneConfigurer(d.Configurer),
+		DBAddress:  d.DBAddress,
+		DBPort:     d.DBPort,
+		Logger:     d.Logger,
+		source:     d.source,
+	}
+	d.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// fields returns the current DBAddress and DBPort
+func (d *DatabaseConfig) fields() []fieldValue {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return []fieldValue{
+		{"DBAddress", "DB_ADDRESS", d.DBAddress},
+		{"DBPort", "DB_PORT", d.DBPort},
+	}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (d *DatabaseConfig) replaceSource(replace func(Source) Source) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.source = replace(d.source)
+}
+
+// layerName returns the name the DatabaseConfig reports its errors under
+func (d *DatabaseConfig) layerName() string {
+	return "database config"
+}
+
+// Unwrap returns the Configurer wrapped by the DatabaseConfig decorator
+func (d *DatabaseConfig) Unwrap() Configurer {
+	return d.Configurer
+}
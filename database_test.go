@@ -0,0 +1,70 @@
+package configdecorator
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// lockedSource is a Source that may be changed while configs reload from it
+type lockedSource struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+func (s *lockedSource) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+func (s *lockedSource) set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func TestChainReadersDuringReload(t *testing.T) {
+	src := &lockedSource{values: map[string]string{}}
+	base := NewConfig("", "", WithSource(src))
+	db := NewDatabaseConfig(base, "", "", WithSource(src))
+	motd := NewMessageOfTheDay(db, "", WithSource(src))
+	if err := motd.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if base.GetPort() == "" || db.GetDBAddress() == "" || db.GetDBPort() == "" || motd.GetMOTD() == "" {
+					t.Error("reader saw an empty field while the chain reloaded")
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 200 {
+		src.set("PORT", strconv.Itoa(9000+i))
+		src.set("DB_PORT", strconv.Itoa(27000+i))
+		if err := motd.Reload(); err != nil {
+			t.Errorf("Reload() error = %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if got := db.GetDBPort(); got != "27199" {
+		t.Errorf("DBPort = %q, want the last reloaded value", got)
+	}
+}
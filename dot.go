@@ -0,0 +1,57 @@
+package configdecorator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+#########################################################################
+# DOT Section - Renders a decorator chain as a Graphviz graph
+#########################################################################
+*/
+
+// ToDOT renders the decorator chain below c as a Graphviz DOT graph for documentation. Every
+// layer is a node labeled with its type and the current values of its known fields, and each
+// layer has an edge to the layer it wraps, from the outermost decorator down to the base config
+func ToDOT(c Configurer) string {
+	var b strings.Builder
+	b.WriteString("digraph config {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for i := 0; c != nil; i++ {
+		label := []string{layerTypeName(c)}
+		for _, field := range layerFields(c) {
+			label = append(label, field.Field+" = "+field.Value)
+		}
+		fmt.Fprintf(&b, "\tlayer%d [label=\"%s\"];\n", i, dotEscape(strings.Join(label, "\n")))
+
+		u, ok := c.(Unwrapper)
+		if !ok || u.Unwrap() == nil {
+			break
+		}
+		fmt.Fprintf(&b, "\tlayer%d -> layer%d;\n", i, i+1)
+		c = u.Unwrap()
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// layerTypeName returns the name of the type of c without the package or pointer
+func layerTypeName(c Configurer) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.Name()
+}
+
+// dotEscape escapes s for use inside a quoted DOT string, newlines become DOT line breaks
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,41 @@
+package configdecorator
+
+import "testing"
+
+func TestToDOT(t *testing.T) {
+	src := MapSource{"PORT": "9000", "MOTD": `say "hi"`}
+	chain := NewGate(NewMessageOfTheDay(NewConfig("", "", WithSource(src)), "", WithSource(src)), "FEATURE", WithSource(src))
+	if err := chain.Unwrap().Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	want := "digraph config {\n" +
+		"\tnode [shape=box];\n" +
+		"\tlayer0 [label=\"Gate\"];\n" +
+		"\tlayer0 -> layer1;\n" +
+		"\tlayer1 [label=\"MessageOfTheDay\\nMOTD = say \\\"hi\\\"\"];\n" +
+		"\tlayer1 -> layer2;\n" +
+		"\tlayer2 [label=\"Config\\nAddress = http://localhost\\nPort = 9000\"];\n" +
+		"}\n"
+	if got := ToDOT(chain); got != want {
+		t.Errorf("ToDOT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestToDOTGenericLayer(t *testing.T) {
+	src := MapSource{"LOG_LEVEL": "debug"}
+	chain := NewEnumField(&countingConfig{}, "LOG_LEVEL", "info", map[string]int{"debug": 0, "info": 1}, WithSource(src))
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	want := "digraph config {\n" +
+		"\tnode [shape=box];\n" +
+		"\tlayer0 [label=\"EnumField[int]\\nLOG_LEVEL = debug\"];\n" +
+		"\tlayer0 -> layer1;\n" +
+		"\tlayer1 [label=\"countingConfig\"];\n" +
+		"}\n"
+	if got := ToDOT(chain); got != want {
+		t.Errorf("ToDOT() =\n%s\nwant\n%s", got, want)
+	}
+}
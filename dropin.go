@@ -0,0 +1,156 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+#########################################################################
+# Drop-In Config Section - Merges a directory of config fragments
+#########################################################################
+*/
+
+// DropInConfig is a decorator that provides the *.conf fragments in a directory to the chain
+// it wraps, like a systemd .d directory. Fragments are merged in lexical order of their file
+// names so a later fragment overrides an earlier one, and every fragment uses the same Format.
+// The directory is read again on every reload so added, removed and changed fragments are
+// picked up, and the merged values are layered under the wrapped layers' sources as FileConfig does
+type DropInConfig struct {
+	Configurer
+	Dir string
+	// Format is "json" or "ini"
+	Format string
+	// MaxFileSize is the largest fragment in bytes that is read, larger fragments return an error
+	MaxFileSize int64
+	// Decryptor decrypts values written with the enc: prefix, such values are an error without one
+	Decryptor Decryptor
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	// values are the merged fragments layered into the wrapped layers' Sources once layered has run
+	values  valueLayer
+	layered sync.Once
+	changeNotifier
+	reloadCounter
+}
+
+// NewDropInConfig creates a new DropInConfig struct that decorates next with the fragments in dir
+func NewDropInConfig(next Configurer, dir, format string) *DropInConfig {
+	d := &DropInConfig{
+		Configurer:  next,
+		Dir:         dir,
+		Format:      format,
+		MaxFileSize: DefaultMaxFileSize,
+	}
+	d.layerValues()
+	return d
+}
+
+// layerValues layers the fragment values into the Sources of the wrapped layers the first time it is called
+func (d *DropInConfig) layerValues() {
+	d.layered.Do(func() {
+		layerSource(d.Configurer, &d.values)
+	})
+}
+
+// Reload reads the fragments and then reloads the wrapped configuration with their values
+// layered under their sources, it implements the Configurer interface
+func (d *DropInConfig) Reload() error {
+	before := d.hooks.before(d)
+	err := d.reload()
+	d.hooks.notify(before, d, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (d *DropInConfig) reload() error {
+	logf(d.Logger, "Reloading drop-in config %s", d.Dir)
+	d.reloaded()
+
+	// Read the fragments first so the wrapped layers bind their values as they reload
+	d.layerValues()
+	values, readErr := d.readFragments()
+	if readErr == nil {
+		d.values.set(values)
+	}
+
+	// Reload the wrapped configuration
+	err := d.Configurer.Reload()
+	return errors.Join(err, layerError(d, readErr))
+}
+
+// readFragments reads every *.conf file in the directory in lexical order and merges their values,
+// a missing directory has no fragments
+func (d *DropInConfig) readFragments() (map[string]string, error) {
+	entries, err := os.ReadDir(d.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading drop-in directory: %w", err)
+	}
+
+	// ReadDir returns the entries sorted by file name
+	values := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+			continue
+		}
+
+		path := filepath.Join(d.Dir, entry.Name())
+		data, err := readConfigFile(path, d.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		fragment, err := parseConfigFile(data, path, d.Format)
+		if err != nil {
+			return nil, err
+		}
+		if fragment, err = decryptValues(fragment, d.Decryptor, path); err != nil {
+			return nil, err
+		}
+		maps.Copy(values, fragment)
+	}
+	return values, nil
+}
+
+// Clone returns a copy of the DropInConfig and the chain it wraps and implements the Cloner interface,
+// the cloned layers read the fragment values of the clone rather than those of the original
+func (d *DropInConfig) Clone() Configurer {
+	d.layerValues()
+	clone := &DropInConfig{
+		Configurer:  cloneConfigurer(d.Configurer),
+		Dir:         d.Dir,
+		Format:      d.Format,
+		MaxFileSize: d.MaxFileSize,
+		Decryptor:   d.Decryptor,
+		Logger:      d.Logger,
+	}
+	clone.values.set(d.values.copyValues())
+	relayerSource(clone.Configurer, &d.values, &clone.values)
+	clone.layered.Do(func() {})
+	d.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// unlayer clears the values layered under the wrapped layers and implements the valueDecorator interface
+func (d *DropInConfig) unlayer() {
+	d.values.set(nil)
+}
+
+// layerName returns the name the DropInConfig reports its errors under
+func (d *DropInConfig) layerName() string {
+	return "drop-in config " + d.Dir
+}
+
+// Unwrap returns the Configurer wrapped by the DropInConfig decorator
+func (d *DropInConfig) Unwrap() Configurer {
+	return d.Configurer
+}
@@ -0,0 +1,49 @@
+package configdecorator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDropInConfigMergesFragmentsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	// Written out of order so the merge order comes from the names, not the creation order
+	writeFile(t, dir, "30-last.conf", `{"MOTD": "from 30"}`)
+	writeFile(t, dir, "10-first.conf", `{"PORT": "9010", "ADDRESS": "http://first", "MOTD": "from 10"}`)
+	writeFile(t, dir, "20-second.conf", `{"PORT": "9020", "MOTD": "from 20"}`)
+	writeFile(t, dir, "40-ignored.txt", `{"MOTD": "not a fragment"}`)
+
+	src := MapSource{}
+	base := NewConfig("", "", WithSource(src))
+	motd := NewMessageOfTheDay(base, "", WithSource(src))
+	d := NewDropInConfig(motd, dir, "json")
+	if err := d.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := motd.GetMOTD(); got != "from 30" {
+		t.Errorf("MOTD = %q, want the last fragment's value", got)
+	}
+	if got := base.GetPort(); got != "9020" {
+		t.Errorf("Port = %q, want the second fragment to override the first", got)
+	}
+	if got := base.GetAddress(); got != "http://first" {
+		t.Errorf("Address = %q, want the only fragment setting it", got)
+	}
+}
+
+func TestDropInConfigMaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-big.conf", `{"MOTD": "`+strings.Repeat("x", 64)+`"}`)
+
+	d := NewDropInConfig(NewConfig("", "", WithSource(MapSource{})), dir, "json")
+	d.MaxFileSize = 32
+	if err := d.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the fragment to exceed MaxFileSize")
+	}
+
+	d.MaxFileSize = 1024
+	if err := d.Reload(); err != nil {
+		t.Errorf("Reload() error = %v, want the fragment to fit", err)
+	}
+}
@@ -0,0 +1,137 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+/*
+#########################################################################
+# Enum Field Section - Decorator parsing a key into a typed constant
+#########################################################################
+*/
+
+// EnumField is a decorator that reads Key from the source and parses it into one of the typed
+// constants in Values, e.g. a LogLevel, so callers get a typed value instead of a string.
+// Read the value through Get when the config may be reloaded concurrently
+type EnumField[T comparable] struct {
+	Configurer
+	Key     string
+	Default string
+	Values  map[string]T
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	value  T
+	name   string
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewEnumField creates a new EnumField struct that decorates the next Configurer, def is the
+// name used when key is not set and must be one of the names in values
+func NewEnumField[T comparable](next Configurer, key, def string, values map[string]T, opts ...Option) *EnumField[T] {
+	o := applyOptions(opts)
+	return &EnumField[T]{
+		Configurer: next,
+		Key:        key,
+		Default:    def,
+		Values:     values,
+		value:      values[def],
+		name:       def,
+		source:     o.source,
+	}
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface.
+// An unknown name leaves the current value in place and returns an error listing the valid names
+func (e *EnumField[T]) Reload() error {
+	before := e.hooks.before(e)
+	err := e.reload()
+	e.hooks.notify(before, e, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (e *EnumField[T]) reload() error {
+	logf(e.Logger, "Reloading enum field %s", e.Key)
+	e.reloaded()
+
+	// Reload the base configuration
+	err := e.Configurer.Reload()
+
+	// Only take this layer's lock once the wrapped configuration has finished reloading
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Load the value from the source, the default only applies when the key is not set
+	name := lookup(e.source, e.Key, e.Default)
+
+	value, ok := e.Values[name]
+	if !ok {
+		valid := make([]string, 0, len(e.Values))
+		for name := range e.Values {
+			valid = append(valid, name)
+		}
+		slices.Sort(valid)
+		parseErr := fmt.Errorf("invalid %s %q: must be one of %s", e.Key, name, strings.Join(valid, ", "))
+		return errors.Join(err, layerError(e, parseErr))
+	}
+	e.value = value
+	e.name = name
+	return err
+}
+
+// Get returns the typed value and is safe to call while the config is reloading
+func (e *EnumField[T]) Get() T {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.value
+}
+
+// Clone returns a copy of the EnumField and the chain it wraps and implements the Cloner interface
+func (e *EnumField[T]) Clone() Configurer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	clone := &EnumField[T]{
+		Configurer: cloneConfigurer(e.Configurer),
+		Key:        e.Key,
+		Default:    e.Default,
+		Values:     e.Values,
+		Logger:     e.Logger,
+		value:      e.value,
+		name:       e.name,
+		source:     e.source,
+	}
+	e.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// fields returns the name of the current value, the field is named after its Key
+func (e *EnumField[T]) fields() []fieldValue {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return []fieldValue{{e.Key, e.Key, e.name}}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (e *EnumField[T]) replaceSource(replace func(Source) Source) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.source = replace(e.source)
+}
+
+// layerName returns the name the EnumField reports its errors under
+func (e *EnumField[T]) layerName() string {
+	return "enum field " + e.Key
+}
+
+// Unwrap returns the Configurer wrapped by the EnumField decorator
+func (e *EnumField[T]) Unwrap() Configurer {
+	return e.Configurer
+}
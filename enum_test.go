@@ -0,0 +1,51 @@
+package configdecorator
+
+import (
+	"strings"
+	"testing"
+)
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+)
+
+var logLevels = map[string]logLevel{"debug": levelDebug, "info": levelInfo, "warn": levelWarn}
+
+func TestEnumField(t *testing.T) {
+	src := MapSource{}
+	e := NewEnumField(&countingConfig{}, "LOG_LEVEL", "info", logLevels, WithSource(src))
+	if got := e.Get(); got != levelInfo {
+		t.Errorf("Get() before Reload = %v, want the default", got)
+	}
+
+	// Unset uses the default
+	if err := e.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := e.Get(); got != levelInfo {
+		t.Errorf("Get() = %v, want the default for an unset key", got)
+	}
+
+	// A valid name selects its value
+	src["LOG_LEVEL"] = "warn"
+	if err := e.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := e.Get(); got != levelWarn {
+		t.Errorf("Get() = %v, want warn", got)
+	}
+
+	// An invalid name keeps the current value and lists the valid names
+	src["LOG_LEVEL"] = "loud"
+	err := e.Reload()
+	if err == nil || !strings.Contains(err.Error(), "must be one of debug, info, warn") {
+		t.Fatalf("Reload() error = %v, want the sorted valid names", err)
+	}
+	if got := e.Get(); got != levelWarn {
+		t.Errorf("Get() = %v, want the last valid value kept", got)
+	}
+}
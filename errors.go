@@ -0,0 +1,142 @@
+package configdecorator
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+/*
+#########################################################################
+# Errors Section - Errors reported by the layers of a chain
+#########################################################################
+*/
+
+// LayerError records an error returned by a single layer of the chain while reloading.
+// A decorator keeps reloading after the layer below it fails and joins every failure with
+// errors.Join, so the error returned by the outermost layer reports each failing layer and
+// errors.Is and errors.As match any of the individual errors
+type LayerError struct {
+	Layer string
+	Err   error
+	layer namedLayer
+}
+
+// Error returns the layer name followed by the error it returned
+func (e *LayerError) Error() string {
+	return e.Layer + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the layer
+func (e *LayerError) Unwrap() error {
+	return e.Err
+}
+
+// layerError wraps err in a LayerError for layer, a nil err stays nil
+func layerError(layer namedLayer, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &LayerError{Layer: layer.layerName(), Err: err, layer: layer}
+}
+
+// namedLayer is implemented by the layers that report their reload errors as a LayerError
+type namedLayer interface {
+	layerName() string
+	reloadCount() uint64
+}
+
+// reloadCounter is embedded by the named layers to count how often they ran, so ReloadMulti can
+// tell a layer that loaded cleanly from one that a decorator above it never reloaded
+type reloadCounter struct {
+	reloads atomic.Uint64
+}
+
+// reloaded records that the layer ran
+func (r *reloadCounter) reloaded() {
+	r.reloads.Add(1)
+}
+
+// reloadCount returns how often the layer ran
+func (r *reloadCounter) reloadCount() uint64 {
+	return r.reloads.Load()
+}
+
+// ErrLayerSkipped is reported by ReloadMulti for a layer that was not reloaded, e.g. one below a
+// Gate that is off, a VersionedConfig that rejected the version or a ThrottledConfig that rate limited
+var ErrLayerSkipped = errors.New("layer skipped")
+
+// UnattributedLayer is the ReloadMulti key for errors not reported by a named layer, e.g. ErrRateLimited
+const UnattributedLayer = "chain"
+
+// ReloadMulti reloads c once and reports the outcome per layer, keyed by the name each layer of
+// the chain reports its errors under, e.g. "database config" or "file config app.json". A layer
+// that loaded cleanly maps to nil and one that was never reloaded maps to ErrLayerSkipped. Layers
+// sharing a name are keyed "name #2", "name #3" from the outermost in. Errors that did not come
+// from a named layer are joined under UnattributedLayer
+func ReloadMulti(c Configurer) map[string]error {
+	var layers []namedLayer
+	keys := make(map[namedLayer]string)
+	seen := make(map[string]int)
+	for layer := c; layer != nil; {
+		if named, ok := layer.(namedLayer); ok {
+			key := named.layerName()
+			if seen[key]++; seen[key] > 1 {
+				key += " #" + strconv.Itoa(seen[key])
+			}
+			layers = append(layers, named)
+			keys[named] = key
+		}
+		u, ok := layer.(Unwrapper)
+		if !ok {
+			break
+		}
+		layer = u.Unwrap()
+	}
+
+	counts := make([]uint64, len(layers))
+	for i, layer := range layers {
+		counts[i] = layer.reloadCount()
+	}
+	err := c.Reload()
+
+	results := make(map[string]error, len(layers))
+	for i, layer := range layers {
+		if layer.reloadCount() == counts[i] {
+			results[keys[layer]] = ErrLayerSkipped
+		} else {
+			results[keys[layer]] = nil
+		}
+	}
+
+	var unattributed []error
+	collectLayerErrors(err, keys, results, &unattributed)
+	if len(unattributed) > 0 {
+		results[UnattributedLayer] = errors.Join(unattributed...)
+	}
+	return results
+}
+
+// collectLayerErrors walks the errors joined into err and records each LayerError under the key
+// of its layer, any other error is appended to unattributed
+func collectLayerErrors(err error, keys map[namedLayer]string, results map[string]error, unattributed *[]error) {
+	switch e := err.(type) {
+	case nil:
+	case *LayerError:
+		key, ok := keys[e.layer]
+		if !ok {
+			key = e.Layer
+		}
+		if prev := results[key]; prev != nil {
+			results[key] = errors.Join(prev, e.Err)
+		} else {
+			results[key] = e.Err
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			collectLayerErrors(inner, keys, results, unattributed)
+		}
+	default:
+		*unattributed = append(*unattributed, err)
+	}
+}
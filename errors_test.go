@@ -0,0 +1,117 @@
+package configdecorator
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// failingConfig is a base Configurer whose Reload always returns err
+type failingConfig struct {
+	err error
+}
+
+func (f *failingConfig) Reload() error {
+	return f.err
+}
+
+func TestReloadJoinsLayerErrors(t *testing.T) {
+	errBase := errors.New("base failed")
+	src := MapSource{"MAX_WORKERS": "many", "ADDRESSES": "nope"}
+	chain := NewAddressesConfig(NewResourceAwareConfig(&failingConfig{err: errBase}, WithSource(src)), WithSource(src))
+
+	err := chain.Reload()
+	if err == nil {
+		t.Fatal("Reload() = nil, want the joined layer errors")
+	}
+
+	if !errors.Is(err, errBase) {
+		t.Errorf("errors.Is(err, errBase) = false, want the base error to be matched")
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("errors.Is(err, strconv.ErrSyntax) = false, want the MAX_WORKERS parse error to be matched")
+	}
+
+	var layers []string
+	for _, joined := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var layerErr *LayerError
+		if errors.As(joined, &layerErr) {
+			layers = append(layers, layerErr.Layer)
+		}
+	}
+	want := []string{"resource aware config", "weighted addresses"}
+	if len(layers) != len(want) {
+		t.Fatalf("LayerError layers = %v, want %v", layers, want)
+	}
+	for i := range want {
+		if layers[i] != want[i] {
+			t.Errorf("LayerError layers = %v, want %v", layers, want)
+			break
+		}
+	}
+}
+
+func TestReloadCleanChainReturnsNil(t *testing.T) {
+	src := MapSource{}
+	chain := NewMessageOfTheDay(NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src)), "", WithSource(src))
+
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() = %v, want nil", err)
+	}
+}
+
+func TestLayerErrorNilStaysNil(t *testing.T) {
+	if err := layerError(NewConfig("", ""), nil); err != nil {
+		t.Fatalf("layerError(nil) = %v, want nil", err)
+	}
+}
+
+func TestReloadMultiMarksSkippedLayers(t *testing.T) {
+	src := MapSource{"CONFIG_VERSION": "1"}
+	chain := NewVersionedConfig(NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src)), 2, WithSource(src))
+
+	results := ReloadMulti(chain)
+	if results["config version"] == nil || errors.Is(results["config version"], ErrLayerSkipped) {
+		t.Errorf(`results["config version"] = %v, want the version mismatch`, results["config version"])
+	}
+	for _, layer := range []string{"database config", "base config"} {
+		if !errors.Is(results[layer], ErrLayerSkipped) {
+			t.Errorf("results[%q] = %v, want ErrLayerSkipped", layer, results[layer])
+		}
+	}
+
+	src["CONFIG_VERSION"] = "2"
+	for layer, err := range ReloadMulti(chain) {
+		if err != nil {
+			t.Errorf("results[%q] = %v, want nil once the version matches", layer, err)
+		}
+	}
+}
+
+func TestReloadMultiKeysLayersUniquely(t *testing.T) {
+	levels := map[string]int{"low": 0, "high": 1}
+	src := MapSource{"LOG_LEVEL": "loud", "TRACE_LEVEL": "high"}
+	base := NewConfig("", "", WithSource(src))
+	inner := NewResourceAwareConfig(base, WithSource(MapSource{"MAX_WORKERS": "many"}))
+	chain := NewEnumField(NewEnumField(NewResourceAwareConfig(inner, WithSource(src)), "LOG_LEVEL", "low", levels, WithSource(src)),
+		"TRACE_LEVEL", "low", levels, WithSource(src))
+
+	results := ReloadMulti(chain)
+	want := []string{"enum field TRACE_LEVEL", "enum field LOG_LEVEL", "resource aware config", "resource aware config #2", "base config"}
+	if len(results) != len(want) {
+		t.Errorf("ReloadMulti() = %v, want keys %v", results, want)
+	}
+	for _, layer := range want {
+		if _, ok := results[layer]; !ok {
+			t.Errorf("ReloadMulti() has no %q key, got %v", layer, results)
+		}
+	}
+	if results["enum field LOG_LEVEL"] == nil || results["enum field TRACE_LEVEL"] != nil {
+		t.Errorf("enum results = %v / %v, want only LOG_LEVEL to fail",
+			results["enum field LOG_LEVEL"], results["enum field TRACE_LEVEL"])
+	}
+	if results["resource aware config #2"] == nil || results["resource aware config"] != nil {
+		t.Errorf("resource aware results = %v / %v, want only the inner layer to fail",
+			results["resource aware config"], results["resource aware config #2"])
+	}
+}
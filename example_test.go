@@ -0,0 +1,47 @@
+package configdecorator_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/lkendrickd/configdecorator"
+)
+
+func Example() {
+	src := configdecorator.MapSource{"ADDRESS": "http://webapp", "DB_PORT": "27018", "MOTD": "Hello, World!"}
+	config := configdecorator.NewConfig("", "", configdecorator.WithSource(src))
+	dbConfig := configdecorator.NewDatabaseConfig(config, "", "", configdecorator.WithSource(src))
+	motdConfig := configdecorator.NewMessageOfTheDay(dbConfig, "", configdecorator.WithSource(src))
+
+	// Reloading the outermost decorator reloads the whole chain
+	if err := motdConfig.Reload(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Web App Address: %s, Port: %s\n", config.GetAddress(), config.GetPort())
+	fmt.Printf("Database Address: %s, Port: %s\n", dbConfig.GetDBAddress(), dbConfig.GetDBPort())
+	fmt.Printf("Message of the Day: %s\n", motdConfig.GetMOTD())
+	// Output:
+	// Web App Address: http://webapp, Port: 8081
+	// Database Address: http://localhost, Port: 27018
+	// Message of the Day: Hello, World!
+}
+
+func TestLoggerReceivesReloadMessages(t *testing.T) {
+	var logs bytes.Buffer
+	src := configdecorator.MapSource{}
+	config := configdecorator.NewConfig("", "", configdecorator.WithSource(src))
+	motdConfig := configdecorator.NewMessageOfTheDay(config, "", configdecorator.WithSource(src))
+	motdConfig.Logger = log.New(&logs, "", 0)
+
+	if err := motdConfig.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := logs.String(); !strings.Contains(got, "Reloading message of the day") || strings.Contains(got, "base config") {
+		t.Errorf("log = %q, want only the layer with a Logger to log", got)
+	}
+}
@@ -0,0 +1,23 @@
+package configdecorator
+
+import "expvar"
+
+/*
+#########################################################################
+# Expvar Section - Publishes the effective config through expvar
+#########################################################################
+*/
+
+// PublishExpvar publishes the effective configuration of c under name with the expvar package,
+// so it is served as a JSON object keyed by field name on the /debug/vars endpoint. The values
+// are read from c every time the var is read, so they always reflect the last reload. Like
+// expvar.Publish it panics when name is already in use
+func PublishExpvar(name string, c Configurer) {
+	expvar.Publish(name, expvar.Func(func() any {
+		values := make(map[string]string)
+		for _, field := range snapshot(c) {
+			values[field.Field] = field.Value
+		}
+		return values
+	}))
+}
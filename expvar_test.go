@@ -0,0 +1,44 @@
+package configdecorator
+
+import (
+	"encoding/json"
+	"expvar"
+	"maps"
+	"testing"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	src := MapSource{"PORT": "9000", "MOTD": "hi"}
+	chain := NewMessageOfTheDay(NewConfig("", "", WithSource(src)), "", WithSource(src))
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	PublishExpvar("configdecorator_test", chain)
+
+	read := func() map[string]string {
+		t.Helper()
+		v := expvar.Get("configdecorator_test")
+		if v == nil {
+			t.Fatal("expvar.Get() = nil, want the published var")
+		}
+		var values map[string]string
+		if err := json.Unmarshal([]byte(v.String()), &values); err != nil {
+			t.Fatalf("decoding expvar %s: %v", v.String(), err)
+		}
+		return values
+	}
+
+	want := map[string]string{"MOTD": "hi", "Address": "http://localhost", "Port": "9000"}
+	if got := read(); !maps.Equal(got, want) {
+		t.Errorf("expvar = %v, want %v", got, want)
+	}
+
+	// The var reads the chain on every read so it follows reloads
+	src["PORT"] = "9001"
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := read()["Port"]; got != "9001" {
+		t.Errorf("expvar Port = %q after reload, want 9001", got)
+	}
+}
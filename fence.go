@@ -0,0 +1,71 @@
+package configdecorator
+
+import (
+	"errors"
+	"sync"
+)
+
+/*
+#########################################################################
+# Reload Fence Section - Decorator serializing overlapping reloads
+#########################################################################
+*/
+
+// ErrReloadInProgress is returned by FencedConfig when RejectConcurrent is set and a reload is already running
+var ErrReloadInProgress = errors.New("reload already in progress")
+
+// FencedConfig is a decorator that lets only one reload of the wrapped configuration run at a
+// time, so a manual Reload racing a watch triggered one cannot interleave with it. By default a
+// reload arriving while another runs waits for it and returns its result instead of reloading again
+type FencedConfig struct {
+	Configurer
+	// RejectConcurrent makes an overlapping reload return ErrReloadInProgress instead of waiting
+	RejectConcurrent bool
+
+	mu       sync.Mutex
+	inFlight *fencedReload
+	changeNotifier
+}
+
+// fencedReload is a running reload that overlapping reloads can wait on
+type fencedReload struct {
+	done chan struct{}
+	err  error
+}
+
+// NewFencedConfig creates a new FencedConfig struct that decorates the next Configurer
+func NewFencedConfig(next Configurer) *FencedConfig {
+	return &FencedConfig{Configurer: next}
+}
+
+// Reload reloads the wrapped configuration unless a reload is already running and implements the Configurer interface
+func (f *FencedConfig) Reload() error {
+	f.mu.Lock()
+	if running := f.inFlight; running != nil {
+		f.mu.Unlock()
+		if f.RejectConcurrent {
+			return ErrReloadInProgress
+		}
+		<-running.done
+		return running.err
+	}
+	reload := &fencedReload{done: make(chan struct{})}
+	f.inFlight = reload
+	f.mu.Unlock()
+
+	// Only the reload that actually runs notifies, the ones waiting on it share its result
+	before := f.hooks.before(f)
+	reload.err = f.Configurer.Reload()
+	f.hooks.notify(before, f, reload.err)
+
+	f.mu.Lock()
+	f.inFlight = nil
+	f.mu.Unlock()
+	close(reload.done)
+	return reload.err
+}
+
+// Unwrap returns the Configurer wrapped by the FencedConfig decorator
+func (f *FencedConfig) Unwrap() Configurer {
+	return f.Configurer
+}
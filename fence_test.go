@@ -0,0 +1,91 @@
+package configdecorator
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingConfig is a base Configurer whose Reload blocks until release is closed and tracks overlapping reloads
+type blockingConfig struct {
+	started   chan struct{}
+	release   chan struct{}
+	active    atomic.Int32
+	maxActive atomic.Int32
+	reloads   atomic.Int32
+	err       error
+}
+
+func (b *blockingConfig) Reload() error {
+	n := b.active.Add(1)
+	defer b.active.Add(-1)
+	for {
+		m := b.maxActive.Load()
+		if n <= m || b.maxActive.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	b.reloads.Add(1)
+	b.started <- struct{}{}
+	<-b.release
+	return b.err
+}
+
+func TestFencedConfigDoesNotInterleave(t *testing.T) {
+	errReload := errors.New("reload failed")
+	inner := &blockingConfig{started: make(chan struct{}, 16), release: make(chan struct{}), err: errReload}
+	f := NewFencedConfig(inner)
+
+	// Start one reload and wait until it runs so the others overlap it
+	results := make(chan error, 8)
+	go func() { results <- f.Reload() }()
+	<-inner.started
+
+	var wg sync.WaitGroup
+	for range 7 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- f.Reload()
+		}()
+	}
+	close(inner.release)
+	wg.Wait()
+
+	for range 8 {
+		if err := <-results; !errors.Is(err, errReload) {
+			t.Errorf("Reload() error = %v, want every caller to get the running reload's result", err)
+		}
+	}
+	if got := inner.maxActive.Load(); got != 1 {
+		t.Errorf("max overlapping reloads = %d, want 1", got)
+	}
+}
+
+func TestFencedConfigRejectConcurrent(t *testing.T) {
+	inner := &blockingConfig{started: make(chan struct{}, 1), release: make(chan struct{})}
+	f := NewFencedConfig(inner)
+	f.RejectConcurrent = true
+
+	done := make(chan error, 1)
+	go func() { done <- f.Reload() }()
+	<-inner.started
+
+	if err := f.Reload(); !errors.Is(err, ErrReloadInProgress) {
+		t.Errorf("overlapping Reload() error = %v, want ErrReloadInProgress", err)
+	}
+	close(inner.release)
+	if err := <-done; err != nil {
+		t.Errorf("running Reload() error = %v", err)
+	}
+
+	// Once the running reload finished the next one goes ahead
+	inner.started = make(chan struct{}, 1)
+	if err := f.Reload(); err != nil {
+		t.Errorf("Reload() after the running one finished = %v, want nil", err)
+	}
+	if got := inner.reloads.Load(); got != 2 {
+		t.Errorf("inner reloads = %d, want 2", got)
+	}
+}
@@ -0,0 +1,327 @@
+package configdecorator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+#########################################################################
+# File Config Section - Decorator loading values from a config file
+#########################################################################
+*/
+
+// FileConfig is a decorator that provides the values of a JSON or INI config file to the chain
+// it wraps. A JSON file uses the lower case environment variable names as keys, e.g.
+//
+//	{"address": "http://webapp", "port": "8080", "db_address": "http://mongodb", "db_port": 27017, "motd": "Hello"}
+//
+// The file is read before the wrapped layers reload and its values are layered into their
+// Sources, so every layer binds the final value in one pass. Values apply in this order,
+// each overriding the one before it:
+//  1. the default from the struct tag
+//  2. the value from the config file, from the outermost FileConfig when several are stacked
+//  3. a value explicitly set in the layer's own source, the environment by default
+//
+// A file that fails to read or parse keeps the values of the last successful read in place
+type FileConfig struct {
+	Configurer
+	Path string
+	// Format is "json" or "ini", empty picks the format from the file extension
+	Format string
+	// Optional makes a missing file a no-op instead of an error
+	Optional bool
+	// MaxFileSize is the largest file in bytes that is read, larger files return an error
+	MaxFileSize int64
+	// Decryptor decrypts values written with the enc: prefix, such values are an error without one
+	Decryptor Decryptor
+	// Mmap reads the file through a memory mapping and only parses it again once it changed,
+	// for large files that are reloaded often. It falls back to plain reads where mmap is not supported.
+	// Writers must replace the file by writing a new one and renaming it over Path, truncating the
+	// mapped file in place while it is parsed kills the process with SIGBUS
+	Mmap bool
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	// values are the file values layered into the wrapped layers' Sources once layered has run
+	values  valueLayer
+	layered sync.Once
+
+	// mu guards the values parsed from the mapped file and the file state they were parsed from
+	mu            sync.Mutex
+	mapped        map[string]string
+	mappedModTime time.Time
+	mappedSize    int64
+	changeNotifier
+	reloadCounter
+}
+
+// NewFileConfig creates a new FileConfig struct that decorates inner with the values in the file at path
+func NewFileConfig(inner Configurer, path string) *FileConfig {
+	f := &FileConfig{
+		Configurer:  inner,
+		Path:        path,
+		MaxFileSize: DefaultMaxFileSize,
+	}
+	f.layerValues()
+	return f
+}
+
+// layerValues layers the file values into the Sources of the wrapped layers the first time it is called
+func (f *FileConfig) layerValues() {
+	f.layered.Do(func() {
+		layerSource(f.Configurer, &f.values)
+	})
+}
+
+// Reload reads the config file and then reloads the wrapped configuration with the file
+// values layered under their sources, it implements the Configurer interface
+func (f *FileConfig) Reload() error {
+	before := f.hooks.before(f)
+	err := f.reload()
+	f.hooks.notify(before, f, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (f *FileConfig) reload() error {
+	logf(f.Logger, "Reloading file config %s", f.Path)
+	f.reloaded()
+
+	// Read the file first so the wrapped layers bind its values as they reload
+	f.layerValues()
+	values, readErr := f.readFile()
+	if readErr == nil {
+		f.values.set(values)
+	}
+
+	// Reload the wrapped configuration
+	err := f.Configurer.Reload()
+	return errors.Join(err, layerError(f, readErr))
+}
+
+// readFile reads and parses the config file into values keyed by environment variable name
+func (f *FileConfig) readFile() (map[string]string, error) {
+	read := f.parseFile
+	if f.Mmap {
+		read = f.parseMappedFile
+	}
+
+	values, err := read()
+	if errors.Is(err, fs.ErrNotExist) && f.Optional {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decryptValues(values, f.Decryptor, f.Path)
+}
+
+// parseFile reads the whole config file onto the heap and parses it
+func (f *FileConfig) parseFile() (map[string]string, error) {
+	data, err := readConfigFile(f.Path, f.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigFile(data, f.Path, f.format())
+}
+
+// parseMappedFile parses the config file through a memory mapping, the file is only mapped and
+// parsed again when its modification time or size changed since the last reload
+func (f *FileConfig) parseMappedFile() (map[string]string, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.mapped != nil && info.ModTime().Equal(f.mappedModTime) && info.Size() == f.mappedSize {
+		return maps.Clone(f.mapped), nil
+	}
+
+	data, unmap, err := mapConfigFile(f.Path, f.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	// The parsers copy every value out of data so it can be unmapped straight away
+	values, err := parseConfigFile(data, f.Path, f.format())
+	if unmapErr := unmap(); err == nil && unmapErr != nil {
+		err = fmt.Errorf("unmapping config file: %w", unmapErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.mapped, f.mappedModTime, f.mappedSize = values, info.ModTime(), info.Size()
+	return maps.Clone(values), nil
+}
+
+// format returns the Format, or the one named by the file extension when the Format is empty
+func (f *FileConfig) format() string {
+	return configFileFormat(f.Path, f.Format)
+}
+
+// configFileFormat returns format, or the one named by the extension of path when format is empty
+func configFileFormat(path, format string) string {
+	if format != "" {
+		return format
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// parseConfigFile parses data read from path in format, "json" or "ini"
+func parseConfigFile(data []byte, path, format string) (map[string]string, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return parseJSONConfig(data, path)
+	case "ini":
+		return parseINIConfig(data, path)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q for %s", format, path)
+	}
+}
+
+// DefaultMaxFileSize is the MaxFileSize of a new FileConfig or DropInConfig
+const DefaultMaxFileSize = 10 << 20
+
+// readConfigFile reads the file at path, refusing files larger than maxSize bytes so a bad
+// source cannot exhaust memory. The size is checked before reading and enforced while reading
+// in case the file grows in between, a maxSize of 0 or less uses DefaultMaxFileSize
+func readConfigFile(path string, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("config file %s is %d bytes, larger than the %d byte limit", path, info.Size(), maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("config file %s is larger than the %d byte limit", path, maxSize)
+	}
+	return data, nil
+}
+
+// parseJSONConfig parses a JSON object of lower case keys into values keyed by environment
+// variable name, values may be strings, numbers or booleans
+func parseJSONConfig(data []byte, path string) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, msg := range raw {
+		var value any
+		decoder := json.NewDecoder(bytes.NewReader(msg))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		switch v := value.(type) {
+		case string:
+			values[strings.ToUpper(name)] = v
+		case json.Number:
+			values[strings.ToUpper(name)] = v.String()
+		case bool:
+			values[strings.ToUpper(name)] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("parsing %s: %q must be a string, a number or a boolean", path, name)
+		}
+	}
+	return values, nil
+}
+
+// Decryptor decrypts the encrypted values of a config file, it is left to the application so the
+// package does not depend on a particular crypto library
+type Decryptor interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// encryptedPrefix marks a config file value as ciphertext to be passed to the Decryptor
+const encryptedPrefix = "enc:"
+
+// decryptValues replaces every value carrying the enc: prefix with its decrypted plaintext,
+// the ciphertext after the prefix is handed to d as is
+func decryptValues(values map[string]string, d Decryptor, path string) (map[string]string, error) {
+	for key, value := range values {
+		ciphertext, ok := strings.CutPrefix(value, encryptedPrefix)
+		if !ok {
+			continue
+		}
+		if d == nil {
+			return nil, fmt.Errorf("%s: %s is encrypted but no Decryptor is set", path, strings.ToLower(key))
+		}
+
+		plaintext, err := d.Decrypt([]byte(ciphertext))
+		if err != nil {
+			return nil, fmt.Errorf("%s: decrypting %s: %w", path, strings.ToLower(key), err)
+		}
+		values[key] = string(plaintext)
+	}
+	return values, nil
+}
+
+// Clone returns a copy of the FileConfig and the chain it wraps and implements the Cloner interface,
+// the cloned layers read the file values of the clone rather than those of the original
+func (f *FileConfig) Clone() Configurer {
+	f.layerValues()
+	clone := &FileConfig{
+		Configurer:  cloneConfigurer(f.Configurer),
+		Path:        f.Path,
+		Format:      f.Format,
+		Optional:    f.Optional,
+		MaxFileSize: f.MaxFileSize,
+		Decryptor:   f.Decryptor,
+		Mmap:        f.Mmap,
+		Logger:      f.Logger,
+	}
+	clone.values.set(f.values.copyValues())
+	relayerSource(clone.Configurer, &f.values, &clone.values)
+	clone.layered.Do(func() {})
+	f.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// unlayer clears the values layered under the wrapped layers and implements the valueDecorator interface
+func (f *FileConfig) unlayer() {
+	f.values.set(nil)
+}
+
+// layerName returns the name the FileConfig reports its errors under
+func (f *FileConfig) layerName() string {
+	return "file config " + f.Path
+}
+
+// Unwrap returns the Configurer wrapped by the FileConfig decorator
+func (f *FileConfig) Unwrap() Configurer {
+	return f.Configurer
+}
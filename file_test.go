@@ -0,0 +1,281 @@
+package configdecorator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeFile writes content to name in dir and returns its path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileConfigPrecedence(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"db_address": "http://mongo", "db_port": 27017}`)
+	src := MapSource{"DB_PORT": "1234"}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	file := NewFileConfig(db, path)
+
+	if err := file.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if got := db.GetDBAddress(); got != "http://mongo" {
+		t.Errorf("DBAddress = %q, want the file value %q", got, "http://mongo")
+	}
+	if got := db.GetDBPort(); got != "1234" {
+		t.Errorf("DBPort = %q, want the explicitly set source value %q", got, "1234")
+	}
+	if got := db.Configurer.(*Config).GetPort(); got != "8081" {
+		t.Errorf("Port = %q, want the tag default %q", got, "8081")
+	}
+}
+
+func TestFileConfigInnerHooksDoNotSeeDefaults(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"db_address": "http://mongo"}`)
+	src := MapSource{}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	file := NewFileConfig(db, path)
+
+	var changes []Change
+	db.OnChange(func(c []Change) {
+		changes = append(changes, c...)
+	})
+	for range 3 {
+		if err := file.Reload(); err != nil {
+			t.Fatalf("Reload() = %v", err)
+		}
+	}
+	if len(changes) != 0 {
+		t.Errorf("inner OnChange fired %v, want no changes when the file does not change", changes)
+	}
+}
+
+func TestFileConfigConcurrentReadersSeeFileValue(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"db_address": "http://mongo"}`)
+	src := MapSource{}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	file := NewFileConfig(db, path)
+	if err := file.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if got := db.GetDBAddress(); got != "http://mongo" {
+				t.Errorf("GetDBAddress() = %q during reload, want %q", got, "http://mongo")
+				return
+			}
+		}
+	}()
+
+	for range 100 {
+		if err := file.Reload(); err != nil {
+			t.Errorf("Reload() = %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestStackedFileConfigsOutermostWins(t *testing.T) {
+	dir := t.TempDir()
+	inner := writeFile(t, dir, "inner.json", `{"address": "http://inner", "port": "1"}`)
+	outer := writeFile(t, dir, "outer.json", `{"address": "http://outer"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	chain := NewFileConfig(NewFileConfig(base, inner), outer)
+
+	if err := chain.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if got := base.GetAddress(); got != "http://outer" {
+		t.Errorf("Address = %q, want the outer file value", got)
+	}
+	if got := base.GetPort(); got != "1" {
+		t.Errorf("Port = %q, want the inner file value", got)
+	}
+}
+
+func TestFileConfigKeepsValuesOnReadError(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"address": "http://file"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	file := NewFileConfig(base, path)
+	if err := file.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	writeFile(t, dir, "config.json", `{"address": `)
+	if err := file.Reload(); err == nil {
+		t.Fatal("Reload() = nil, want the parse error")
+	}
+	if got := base.GetAddress(); got != "http://file" {
+		t.Errorf("Address = %q after a failed read, want the last good value", got)
+	}
+}
+
+func TestFileConfigMmapReloadsRenamedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	f := NewFileConfig(base, path)
+	f.Mmap = true
+
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := base.GetPort(); got != "9000" {
+		t.Fatalf("Port = %q, want 9000", got)
+	}
+
+	// Replace the file by rename the way Mmap requires of writers
+	next := writeFile(t, dir, "config.json.tmp", `{"PORT": "9001", "ADDRESS": "http://mapped"}`)
+	if err := os.Rename(next, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := base.GetPort(); got != "9001" {
+		t.Errorf("Port = %q, want the renamed file's 9001", got)
+	}
+}
+
+// BenchmarkFileConfigReload compares reloading an unchanged large file through plain reads and a memory mapping
+func BenchmarkFileConfigReload(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i := range 5000 {
+		fmt.Fprintf(&sb, `"KEY_%d": "value %d", `, i, i)
+	}
+	sb.WriteString(`"PORT": "9000"}`)
+	path := filepath.Join(b.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, mmap := range []bool{false, true} {
+		name := "read"
+		if mmap {
+			name = "mmap"
+		}
+		b.Run(name, func(b *testing.B) {
+			f := NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path)
+			f.Mmap = mmap
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if err := f.Reload(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadConfigFileSizeLimit(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "under", size: 99},
+		{name: "at", size: 100},
+		{name: "over", size: 101, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, dir, tt.name+".json", strings.Repeat("x", tt.size))
+			data, err := readConfigFile(path, 100)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(data) != tt.size {
+				t.Errorf("readConfigFile() read %d bytes, want %d", len(data), tt.size)
+			}
+		})
+	}
+}
+
+func TestFileConfigMaxFileSize(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"PORT": "9000"}`)
+	for _, mmap := range []bool{false, true} {
+		f := NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path)
+		f.Mmap = mmap
+		f.MaxFileSize = 8
+		if err := f.Reload(); err == nil {
+			t.Errorf("Reload() with Mmap %v error = nil, want the file to exceed MaxFileSize", mmap)
+		}
+	}
+}
+
+// reverseDecryptor is a Decryptor whose ciphertext is the plaintext reversed, "bad" fails to decrypt
+type reverseDecryptor struct{}
+
+func (reverseDecryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	if string(ciphertext) == "bad" {
+		return nil, fmt.Errorf("cannot decrypt")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		plaintext[len(ciphertext)-1-i] = b
+	}
+	return plaintext, nil
+}
+
+func TestFileConfigDecryptsValues(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"DB_ADDRESS": "enc:bd//:ptth", "DB_PORT": "27017"}`)
+	src := MapSource{}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	f := NewFileConfig(db, path)
+	f.Decryptor = reverseDecryptor{}
+
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := db.GetDBAddress(); got != "http://db" {
+		t.Errorf("DBAddress = %q, want the decrypted value", got)
+	}
+	if got := db.GetDBPort(); got != "27017" {
+		t.Errorf("DBPort = %q, want the plain value untouched", got)
+	}
+}
+
+func TestFileConfigDecryptErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		name      string
+		content   string
+		decryptor Decryptor
+		want      string
+	}{
+		{name: "no decryptor", content: `{"DB_ADDRESS": "enc:bd//:ptth"}`, want: "db_address is encrypted but no Decryptor is set"},
+		{name: "decrypt error", content: `{"DB_ADDRESS": "enc:bad"}`, decryptor: reverseDecryptor{}, want: "decrypting db_address: cannot decrypt"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, dir, "config.json", tt.content)
+			f := NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path)
+			f.Decryptor = tt.decryptor
+			if err := f.Reload(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Reload() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
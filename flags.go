@@ -0,0 +1,86 @@
+package configdecorator
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/*
+#########################################################################
+# Flags Section - Struct tag driven registration of command line flags
+#########################################################################
+*/
+
+// RegisterFlags registers a flag on fs for every field of the struct pointed to by target that
+// has a flag tag, e.g.
+//
+//	Address string `flag:"address" usage:"address to listen on"`
+//
+// The flag tag names the flag, the usage tag its help text, and the field's current value is the
+// flag's default so values loaded with Bind beforehand show up as defaults. The flags write straight
+// into the fields when fs is parsed, build the configs with a FlagSource over fs so a later Reload
+// keeps the parsed values instead of binding the environment over them. String, int, bool and time.Duration fields are supported,
+// fields of another type return an error and fields without a flag tag, unexported fields and
+// embedded fields are skipped
+func RegisterFlags(fs *flag.FlagSet, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("flags: target must be a non-nil pointer to a struct, got %T", target)
+	}
+	v = v.Elem()
+
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, ok := field.Tag.Lookup("flag")
+		if !ok || field.Anonymous || !field.IsExported() {
+			continue
+		}
+		usage := field.Tag.Get("usage")
+
+		switch p := v.Field(i).Addr().Interface().(type) {
+		case *string:
+			fs.StringVar(p, name, *p, usage)
+		case *int:
+			fs.IntVar(p, name, *p, usage)
+		case *bool:
+			fs.BoolVar(p, name, *p, usage)
+		case *time.Duration:
+			fs.DurationVar(p, name, *p, usage)
+		default:
+			errs = append(errs, fmt.Errorf("registering flag %s for %s: unsupported field type %s", name, field.Name, field.Type))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FlagSource is a Source backed by the flags set on a parsed FlagSet, so a Reload keeps the values
+// given on the command line. A key reads the flag named by lower casing it and replacing underscores
+// with dashes, e.g. DB_ADDRESS reads -db-address, which is how the core configs tag their flags.
+// Keys whose flag was not set on the command line are looked up in Fallback, the environment when nil
+type FlagSource struct {
+	FlagSet  *flag.FlagSet
+	Fallback Source
+}
+
+// Get returns the value of the flag for key when it was set and implements the Source interface
+func (s FlagSource) Get(key string) (string, bool) {
+	if s.FlagSet != nil && s.FlagSet.Parsed() {
+		name := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		var value string
+		set := false
+		s.FlagSet.Visit(func(f *flag.Flag) {
+			if f.Name == name {
+				value, set = f.Value.String(), true
+			}
+		})
+		if set {
+			return value, true
+		}
+	}
+	return sourceOrEnv(s.Fallback).Get(key)
+}
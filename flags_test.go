@@ -0,0 +1,62 @@
+package configdecorator
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestFlagSourceKeepsParsedFlagsOnReload(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	src := FlagSource{FlagSet: fs, Fallback: MapSource{"ADDRESS": "x", "PORT": "9000", "DB_PORT": "1"}}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	base := db.Unwrap().(*Config)
+	if err := RegisterFlags(fs, base); err != nil {
+		t.Fatalf("RegisterFlags(Config) error = %v", err)
+	}
+	if err := RegisterFlags(fs, db); err != nil {
+		t.Fatalf("RegisterFlags(DatabaseConfig) error = %v", err)
+	}
+	if err := fs.Parse([]string{"-address=flagval", "-db-port=2"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for range 2 {
+		if err := db.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if got := base.GetAddress(); got != "flagval" {
+			t.Errorf("Address = %q, want the flag value", got)
+		}
+		if got := db.GetDBPort(); got != "2" {
+			t.Errorf("DBPort = %q, want the flag value", got)
+		}
+		if got := base.GetPort(); got != "9000" {
+			t.Errorf("Port = %q, want the fallback value for an unset flag", got)
+		}
+		if got := db.GetDBAddress(); got != "http://localhost" {
+			t.Errorf("DBAddress = %q, want the default when neither flag nor fallback is set", got)
+		}
+	}
+}
+
+func TestFlagSourceBeforeParseUsesFallback(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("address", "", "")
+	src := FlagSource{FlagSet: fs, Fallback: MapSource{"ADDRESS": "x"}}
+
+	if got, ok := src.Get("ADDRESS"); !ok || got != "x" {
+		t.Errorf("Get(ADDRESS) = %q, %v, want the fallback before Parse", got, ok)
+	}
+	if _, ok := src.Get("PORT"); ok {
+		t.Error("Get(PORT) ok = true, want false for a key set nowhere")
+	}
+}
+
+func TestRegisterFlagsRejectsUnsupportedType(t *testing.T) {
+	var target struct {
+		Ratio float64 `flag:"ratio"`
+	}
+	if err := RegisterFlags(flag.NewFlagSet("test", flag.ContinueOnError), &target); err == nil {
+		t.Error("RegisterFlags() error = nil, want an unsupported type error")
+	}
+}
@@ -0,0 +1,164 @@
+package configdecorator
+
+import (
+	"log"
+	"sync"
+)
+
+/*
+#########################################################################
+# Feature Gate Section - Enables or disables a decorator via a feature flag
+#########################################################################
+*/
+
+// Gate wraps a decorator and only applies it when the feature flag environment
+// variable is set to a true value accepted by ParseBool, e.g. FEATURE_REDIS=true
+type Gate struct {
+	Configurer
+	FlagEnvVar string
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+}
+
+// NewGate creates a new Gate struct that gates the next Configurer behind the flagEnvVar feature flag
+func NewGate(next Configurer, flagEnvVar string, opts ...Option) *Gate {
+	o := applyOptions(opts)
+	return &Gate{
+		Configurer: next,
+		FlagEnvVar: flagEnvVar,
+		source:     o.source,
+	}
+}
+
+// Enabled reports whether the feature flag is set to a true value in the source
+func (g *Gate) Enabled() bool {
+	g.mu.RLock()
+	source := g.source
+	g.mu.RUnlock()
+
+	enabled, err := ParseBool(lookup(source, g.FlagEnvVar, ""))
+	return err == nil && enabled
+}
+
+// Reload reloads the wrapped decorator when the feature flag is enabled and implements the Configurer interface.
+// When the flag is disabled the wrapped decorator's own load is skipped and the layer below it is reloaded instead,
+// a gated value decorator such as FileConfig has its values removed from the layers below
+func (g *Gate) Reload() error {
+	before := g.hooks.before(g)
+	err := g.reload()
+	g.hooks.notify(before, g, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (g *Gate) reload() error {
+	if g.Enabled() {
+		return g.Configurer.Reload()
+	}
+
+	logf(g.Logger, "Skipping gated config, %s is not enabled", g.FlagEnvVar)
+
+	// A gated value decorator such as FileConfig must not leave its values layered under the chain
+	if d, ok := g.Configurer.(valueDecorator); ok {
+		d.unlayer()
+	}
+
+	// Pass through to the layer below the gated decorator if there is one
+	if u, ok := g.Configurer.(Unwrapper); ok {
+		return u.Unwrap().Reload()
+	}
+	return nil
+}
+
+// Clone returns a copy of the Gate and the chain it wraps and implements the Cloner interface
+func (g *Gate) Clone() Configurer {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	clone := &Gate{
+		Configurer: cloneConfigurer(g.Configurer),
+		FlagEnvVar: g.FlagEnvVar,
+		Logger:     g.Logger,
+		source:     g.source,
+	}
+	g.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (g *Gate) replaceSource(replace func(Source) Source) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.source = replace(g.source)
+}
+
+// Unwrap returns the Configurer wrapped by the Gate
+func (g *Gate) Unwrap() Configurer {
+	return g.Configurer
+}
+
+/*
+#########################################################################
+# Leader Gate Section - Only reloads a decorator on the elected leader
+#########################################################################
+*/
+
+// LeaderGate wraps a decorator and only reloads it while IsLeader reports true, so in a
+// clustered deployment a remote source is only read by the leader instead of by every replica
+type LeaderGate struct {
+	Configurer
+	IsLeader func() bool
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	changeNotifier
+}
+
+// NewLeaderGate creates a new LeaderGate struct that gates the next Configurer behind the isLeader check
+func NewLeaderGate(next Configurer, isLeader func() bool) *LeaderGate {
+	return &LeaderGate{
+		Configurer: next,
+		IsLeader:   isLeader,
+	}
+}
+
+// Reload reloads the wrapped decorator on the leader and implements the Configurer interface. On any
+// other replica the wrapped decorator keeps its current values and the layer below it is reloaded instead
+func (g *LeaderGate) Reload() error {
+	before := g.hooks.before(g)
+	err := g.reload()
+	g.hooks.notify(before, g, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (g *LeaderGate) reload() error {
+	if g.IsLeader == nil || g.IsLeader() {
+		return g.Configurer.Reload()
+	}
+
+	logf(g.Logger, "Skipping leader gated config, not the leader")
+
+	// Pass through to the layer below the gated decorator if there is one
+	if u, ok := g.Configurer.(Unwrapper); ok {
+		return u.Unwrap().Reload()
+	}
+	return nil
+}
+
+// Clone returns a copy of the LeaderGate and the chain it wraps and implements the Cloner interface
+func (g *LeaderGate) Clone() Configurer {
+	clone := &LeaderGate{
+		Configurer: cloneConfigurer(g.Configurer),
+		IsLeader:   g.IsLeader,
+		Logger:     g.Logger,
+	}
+	g.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// Unwrap returns the Configurer wrapped by the LeaderGate
+func (g *LeaderGate) Unwrap() Configurer {
+	return g.Configurer
+}
@@ -0,0 +1,119 @@
+package configdecorator
+
+import "testing"
+
+func TestGate(t *testing.T) {
+	for _, tt := range []struct {
+		flag     string
+		wantMOTD string
+	}{
+		{flag: "on", wantMOTD: "from source"},
+		{flag: "true", wantMOTD: "from source"},
+		{flag: "off", wantMOTD: "untouched"},
+		{flag: "garbage", wantMOTD: "untouched"},
+		{flag: "", wantMOTD: "untouched"},
+	} {
+		t.Run(tt.flag, func(t *testing.T) {
+			src := MapSource{"MOTD": "from source", "PORT": "9000"}
+			if tt.flag != "" {
+				src["FEATURE_MOTD"] = tt.flag
+			}
+			base := NewConfig("", "", WithSource(src))
+			motd := NewMessageOfTheDay(base, "untouched", WithSource(src))
+			g := NewGate(motd, "FEATURE_MOTD", WithSource(src))
+
+			if err := g.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+			if got := motd.GetMOTD(); got != tt.wantMOTD {
+				t.Errorf("MOTD = %q, want %q", got, tt.wantMOTD)
+			}
+			if got := base.GetPort(); got != "9000" {
+				t.Errorf("Port = %q, want the layer below the gate reloaded either way", got)
+			}
+		})
+	}
+}
+
+func TestLeaderGate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		isLeader func() bool
+		wantMOTD string
+	}{
+		{name: "leader", isLeader: func() bool { return true }, wantMOTD: "from source"},
+		{name: "follower", isLeader: func() bool { return false }, wantMOTD: "untouched"},
+		{name: "no check", wantMOTD: "from source"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			src := MapSource{"MOTD": "from source", "PORT": "9000"}
+			base := NewConfig("", "", WithSource(src))
+			motd := NewMessageOfTheDay(base, "untouched", WithSource(src))
+			g := NewLeaderGate(motd, tt.isLeader)
+
+			if err := g.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+			if got := motd.GetMOTD(); got != tt.wantMOTD {
+				t.Errorf("MOTD = %q, want %q", got, tt.wantMOTD)
+			}
+			if got := base.GetPort(); got != "9000" {
+				t.Errorf("Port = %q, want the layer below the gate reloaded either way", got)
+			}
+		})
+	}
+}
+
+func TestGateFlagFromFile(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.json", `{"feature_motd": "on", "motd": "from file"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	motd := NewMessageOfTheDay(base, "untouched", WithSource(MapSource{}))
+	f := NewFileConfig(NewGate(motd, "FEATURE_MOTD", WithSource(MapSource{})), path)
+
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := motd.GetMOTD(); got != "from file" {
+		t.Errorf("MOTD = %q, want the gate enabled by the file", got)
+	}
+}
+
+func TestGateSwitchedOffUnlayersValues(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		wrap func(t *testing.T, next Configurer) Configurer
+	}{
+		{name: "file", wrap: func(t *testing.T, next Configurer) Configurer {
+			return NewFileConfig(next, writeFile(t, t.TempDir(), "config.json", `{"port": "7777"}`))
+		}},
+		{name: "drop-in", wrap: func(t *testing.T, next Configurer) Configurer {
+			dir := t.TempDir()
+			writeFile(t, dir, "10-port.conf", "port = 7777")
+			return NewDropInConfig(next, dir, "ini")
+		}},
+		{name: "viper", wrap: func(t *testing.T, next Configurer) Configurer {
+			return NewViperConfig(next, &fakeViper{pending: map[string]string{"port": "7777"}})
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := MapSource{"FEATURE_FILE": "on"}
+			base := NewConfig("", "", WithSource(MapSource{}))
+			g := NewGate(tt.wrap(t, base), "FEATURE_FILE", WithSource(flags))
+
+			if err := g.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+			if got := base.GetPort(); got != "7777" {
+				t.Fatalf("Port = %q with the gate on, want 7777 from the gated layer", got)
+			}
+
+			flags["FEATURE_FILE"] = "off"
+			if err := g.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+			if got := base.GetPort(); got != "8081" {
+				t.Errorf("Port = %q with the gate off, want the default 8081", got)
+			}
+		})
+	}
+}
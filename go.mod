@@ -1,3 +1,3 @@
-module github.com/lkendrickd/decorator
+module github.com/lkendrickd/configdecorator
 
 go 1.22.2
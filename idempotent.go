@@ -0,0 +1,79 @@
+package configdecorator
+
+import (
+	"log"
+	"sync"
+)
+
+/*
+#########################################################################
+# Idempotent Reload Section - Skips reloads for already processed keys
+#########################################################################
+*/
+
+// DefaultMaxReloadKeys is the MaxKeys of a new IdempotentConfig
+const DefaultMaxReloadKeys = 1024
+
+// IdempotentConfig is a decorator for at least once reload triggers, such as a message queue,
+// that remembers the keys of recent reloads so a redelivered trigger does not reload again
+type IdempotentConfig struct {
+	Configurer
+	// MaxKeys bounds how many recent keys are remembered, the oldest key is forgotten first
+	MaxKeys int
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	seen   map[string]struct{}
+	order  []string
+	mu     sync.Mutex
+	changeNotifier
+}
+
+// NewIdempotentConfig creates a new IdempotentConfig struct that decorates the next Configurer
+func NewIdempotentConfig(next Configurer) *IdempotentConfig {
+	return &IdempotentConfig{
+		Configurer: next,
+		MaxKeys:    DefaultMaxReloadKeys,
+		seen:       make(map[string]struct{}),
+	}
+}
+
+// Reload reloads the wrapped configuration without a key and implements the Configurer interface
+func (i *IdempotentConfig) Reload() error {
+	before := i.hooks.before(i)
+	err := i.Configurer.Reload()
+	i.hooks.notify(before, i, err)
+	return err
+}
+
+// ReloadWithKey reloads the wrapped configuration unless key was already processed, in which case
+// it returns false without reloading. A failed reload does not record key so the trigger can be retried
+func (i *IdempotentConfig) ReloadWithKey(key string) (bool, error) {
+	// Hold the lock across the reload so a concurrent delivery of the same key waits and is skipped
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.seen[key]; ok {
+		logf(i.Logger, "Skipping reload, key %s was already processed", key)
+		return false, nil
+	}
+
+	before := i.hooks.before(i)
+	err := i.Configurer.Reload()
+	i.hooks.notify(before, i, err)
+	if err != nil {
+		return true, err
+	}
+
+	i.seen[key] = struct{}{}
+	i.order = append(i.order, key)
+	for i.MaxKeys > 0 && len(i.order) > i.MaxKeys {
+		delete(i.seen, i.order[0])
+		i.order = i.order[1:]
+	}
+	return true, nil
+}
+
+// Unwrap returns the Configurer wrapped by the IdempotentConfig decorator
+func (i *IdempotentConfig) Unwrap() Configurer {
+	return i.Configurer
+}
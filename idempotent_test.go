@@ -0,0 +1,82 @@
+package configdecorator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestIdempotentConfigSkipsDuplicateKeys(t *testing.T) {
+	inner := &countingConfig{}
+	i := NewIdempotentConfig(inner)
+
+	for _, tt := range []struct {
+		key        string
+		wantReload bool
+	}{
+		{key: "msg-1", wantReload: true},
+		{key: "msg-1"},
+		{key: "msg-2", wantReload: true},
+		{key: "msg-1"},
+	} {
+		reloaded, err := i.ReloadWithKey(tt.key)
+		if err != nil {
+			t.Fatalf("ReloadWithKey(%s) error = %v", tt.key, err)
+		}
+		if reloaded != tt.wantReload {
+			t.Errorf("ReloadWithKey(%s) = %v, want %v", tt.key, reloaded, tt.wantReload)
+		}
+	}
+	if inner.reloads != 2 {
+		t.Errorf("inner reloads = %d, want 2", inner.reloads)
+	}
+}
+
+func TestIdempotentConfigConcurrentDuplicates(t *testing.T) {
+	inner := &countingConfig{}
+	i := NewIdempotentConfig(inner)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := i.ReloadWithKey("msg-1"); err != nil {
+				t.Errorf("ReloadWithKey() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if inner.reloads != 1 {
+		t.Errorf("inner reloads = %d, want one reload for a key delivered concurrently", inner.reloads)
+	}
+}
+
+func TestIdempotentConfigRetriesFailedKey(t *testing.T) {
+	inner := &failingConfig{err: errors.New("source down")}
+	i := NewIdempotentConfig(inner)
+
+	if _, err := i.ReloadWithKey("msg-1"); err == nil {
+		t.Fatal("ReloadWithKey() error = nil, want the reload error")
+	}
+	inner.err = nil
+	if reloaded, err := i.ReloadWithKey("msg-1"); err != nil || !reloaded {
+		t.Errorf("ReloadWithKey() retry = %v, %v, want the failed key to reload again", reloaded, err)
+	}
+}
+
+func TestIdempotentConfigForgetsOldestKey(t *testing.T) {
+	i := NewIdempotentConfig(&countingConfig{})
+	i.MaxKeys = 2
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := i.ReloadWithKey(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reloaded, _ := i.ReloadWithKey("a"); !reloaded {
+		t.Error("ReloadWithKey(a) skipped, want the oldest key forgotten")
+	}
+	if reloaded, _ := i.ReloadWithKey("c"); reloaded {
+		t.Error("ReloadWithKey(c) reloaded, want a recent key remembered")
+	}
+}
@@ -0,0 +1,87 @@
+package configdecorator
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+#########################################################################
+# INI Config Section - Parses legacy INI config files for FileConfig
+#########################################################################
+*/
+
+// iniKeys maps each "section.key" of an INI config file to the environment variable name it sets,
+// keys outside any section use the lower case environment variable names like the JSON format
+var iniKeys = map[string]string{
+	"server.address":   "ADDRESS",
+	"server.port":      "PORT",
+	"database.address": "DB_ADDRESS",
+	"database.port":    "DB_PORT",
+	"motd.message":     "MOTD",
+}
+
+// NewINIConfig creates a new FileConfig struct that decorates inner with the values in the INI
+// file at path whatever its extension. The [server] and [database] sections set the Config and
+// DatabaseConfig fields and [motd] sets the message, e.g.
+//
+//	[server]
+//	address = http://webapp
+//	port = 8080
+//
+//	[database]
+//	address = http://mongodb
+//	port = 27017
+//
+//	[motd]
+//	message = Hello
+func NewINIConfig(inner Configurer, path string) *FileConfig {
+	f := NewFileConfig(inner, path)
+	f.Format = "ini"
+	return f
+}
+
+// parseINIConfig parses INI key = value lines grouped under [section] headers, lines starting
+// with ';' or '#' are comments and keys in sections other than those in iniKeys are ignored
+func parseINIConfig(data []byte, path string) (map[string]string, error) {
+	values := make(map[string]string)
+	section := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("parsing %s: line %d: unterminated section header %q", path, i+1, line)
+			}
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("parsing %s: line %d: expected key = value, got %q", path, i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		if key, ok := iniKey(section, name); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// iniKey returns the environment variable name set by name in section, keys of unknown sections are ignored
+func iniKey(section, name string) (string, bool) {
+	if section == "" {
+		return strings.ToUpper(name), true
+	}
+	key, ok := iniKeys[section+"."+name]
+	return key, ok
+}
@@ -0,0 +1,64 @@
+package configdecorator
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseINIConfigSections(t *testing.T) {
+	data := []byte(`; legacy config
+log_level = debug
+
+[server]
+address = http://webapp
+port = "8080"
+
+[Database]
+# comments and blank lines are skipped
+address = http://mongodb
+PORT = 27017
+
+[unknown]
+address = ignored
+`)
+	got, err := parseINIConfig(data, "app.ini")
+	if err != nil {
+		t.Fatalf("parseINIConfig() error = %v", err)
+	}
+	want := map[string]string{
+		"LOG_LEVEL":  "debug",
+		"ADDRESS":    "http://webapp",
+		"PORT":       "8080",
+		"DB_ADDRESS": "http://mongodb",
+		"DB_PORT":    "27017",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseINIConfig() = %v, want %v", got, want)
+	}
+}
+
+func TestParseINIConfigMalformed(t *testing.T) {
+	for _, data := range []string{
+		"[server\naddress = x",
+		"[server]\naddress",
+		"[server]\n= x",
+	} {
+		if _, err := parseINIConfig([]byte(data), "app.ini"); err == nil {
+			t.Errorf("parseINIConfig(%q) error = nil, want an error", data)
+		}
+	}
+}
+
+func TestINIConfigReload(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "app.conf", "[database]\nport = 27018\n[motd]\nmessage = Hello\n")
+	src := MapSource{}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	motd := NewMessageOfTheDay(db, "", WithSource(src))
+	f := NewINIConfig(motd, path)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if db.GetDBPort() != "27018" || motd.GetMOTD() != "Hello" {
+		t.Errorf("DBPort, MOTD = %q, %q, want the INI values", db.GetDBPort(), motd.GetMOTD())
+	}
+}
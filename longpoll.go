@@ -0,0 +1,139 @@
+package configdecorator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/*
+#########################################################################
+# Long Poll Section - Reloads the chain when a config server reports a change
+#########################################################################
+*/
+
+// LongPollConfig is a decorator that long-polls a config server and reloads the wrapped
+// configuration whenever the server reports a new version. The server is expected to hold
+// the request until the config changes, answering 200 OK with the new version as the body,
+// or 304 Not Modified when its hold times out
+type LongPollConfig struct {
+	Configurer
+	URL    string
+	Client *http.Client
+	// Version is the last version the wrapped configuration was reloaded for
+	Version    string
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Logger receives debug messages and the errors Run retries when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+}
+
+// minLongPollBackoff is the shortest wait between failed polls, so a zero MinBackoff does not spin
+const minLongPollBackoff = 10 * time.Millisecond
+
+// NewLongPollConfig creates a new LongPollConfig struct that decorates the next Configurer
+// and polls the config server at serverURL
+func NewLongPollConfig(next Configurer, serverURL string) *LongPollConfig {
+	return &LongPollConfig{
+		Configurer: next,
+		URL:        serverURL,
+		Client:     http.DefaultClient,
+		MinBackoff: time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Poll issues a single long-poll request carrying the last seen version and blocks until
+// the server responds, it reports whether the server returned a new version and records it
+func (l *LongPollConfig) Poll(ctx context.Context) (bool, error) {
+	version, changed, err := l.poll(ctx)
+	if changed {
+		l.Version = version
+	}
+	return changed, err
+}
+
+// poll issues a single long-poll request and returns the version the server reported,
+// without recording it so Run can keep the old version until the reload succeeds
+func (l *LongPollConfig) poll(ctx context.Context) (string, bool, error) {
+	u, err := url.Parse(l.URL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid long poll url: %w", err)
+	}
+	q := u.Query()
+	q.Set("version", l.Version)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		// The server timed out holding the request without a change
+		return l.Version, false, nil
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", false, fmt.Errorf("reading long poll response: %w", err)
+		}
+		version := strings.TrimSpace(string(body))
+		return version, version != l.Version, nil
+	default:
+		return "", false, fmt.Errorf("unexpected long poll status: %s", resp.Status)
+	}
+}
+
+// Run long-polls the config server in a loop and reloads the wrapped configuration on every
+// change. Transport errors and failed reloads are logged and retried with exponential backoff,
+// Version only moves on once the reload for it succeeded so a failed one is retried on the next
+// poll. Run returns the context error once ctx is cancelled
+func (l *LongPollConfig) Run(ctx context.Context) error {
+	minBackoff := max(l.MinBackoff, minLongPollBackoff)
+	maxBackoff := max(l.MaxBackoff, minBackoff)
+
+	backoff := minBackoff
+	for {
+		version, changed, err := l.poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil && changed {
+			if err = l.Configurer.Reload(); err != nil {
+				err = fmt.Errorf("reloading after config version %s: %w", version, err)
+			} else {
+				l.Version = version
+			}
+		}
+
+		if err != nil {
+			logf(l.Logger, "Long poll failed, retrying in %s: %v", backoff, err)
+
+			// Wait before retrying so a down server is not hammered
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+	}
+}
+
+// Unwrap returns the Configurer wrapped by the LongPollConfig decorator
+func (l *LongPollConfig) Unwrap() Configurer {
+	return l.Configurer
+}
@@ -0,0 +1,193 @@
+package configdecorator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signalConfig is a base Configurer signalling every reload on reloaded
+type signalConfig struct {
+	reloaded chan struct{}
+}
+
+func (s *signalConfig) Reload() error {
+	s.reloaded <- struct{}{}
+	return nil
+}
+
+func TestLongPollConfigHoldsThenReloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the request like a config server waiting for a change
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		if r.URL.Query().Get("version") == "v2" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("v2\n"))
+	}))
+	defer server.Close()
+
+	inner := &signalConfig{reloaded: make(chan struct{}, 1)}
+	l := NewLongPollConfig(inner, server.URL)
+	l.Version = "v1"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	select {
+	case <-inner.reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not reload after the server reported a new version")
+	}
+
+	// The following polls are answered 304 and must not reload again
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	select {
+	case <-inner.reloaded:
+		t.Error("Run() reloaded on a 304 Not Modified")
+	default:
+	}
+	if l.Version != "v2" {
+		t.Errorf("Version = %q, want v2", l.Version)
+	}
+}
+
+func TestLongPollConfigBacksOffOnErrors(t *testing.T) {
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		n := len(requests)
+		mu.Unlock()
+		if n <= 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("v1"))
+	}))
+	defer server.Close()
+
+	inner := &signalConfig{reloaded: make(chan struct{}, 1)}
+	l := NewLongPollConfig(inner, server.URL)
+	l.MinBackoff, l.MaxBackoff = 20*time.Millisecond, 40*time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	select {
+	case <-inner.reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not recover after the server errors")
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, least := range want {
+		if gap := requests[i+1].Sub(requests[i]); gap < least {
+			t.Errorf("gap before request %d = %v, want at least %v", i+2, gap, least)
+		}
+	}
+}
+
+// flakyConfig is a base Configurer failing its first failures reloads and signalling every reload on reloaded
+type flakyConfig struct {
+	failures int
+	reloaded chan error
+}
+
+func (f *flakyConfig) Reload() error {
+	var err error
+	if f.failures > 0 {
+		f.failures--
+		err = errors.New("push rejected")
+	}
+	f.reloaded <- err
+	return err
+}
+
+func TestLongPollConfigRetriesFailedReload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("version") == "v2" {
+			// Hold the request like a config server waiting for a change
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("v2"))
+	}))
+	defer server.Close()
+
+	inner := &flakyConfig{failures: 1, reloaded: make(chan error, 1)}
+	l := NewLongPollConfig(inner, server.URL)
+	l.Version = "v1"
+	l.MinBackoff = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	for i, wantErr := range []bool{true, false} {
+		select {
+		case err := <-inner.reloaded:
+			if (err != nil) != wantErr {
+				t.Fatalf("reload %d error = %v, wantErr %v", i+1, err, wantErr)
+			}
+		case err := <-done:
+			t.Fatalf("Run() returned %v, want it to keep polling after a failed reload", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Run() stopped after %d reloads, want the failed one retried", i)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if l.Version != "v2" {
+		t.Errorf("Version = %q, want v2 once the retried reload succeeded", l.Version)
+	}
+}
+
+func TestLongPollConfigZeroBackoffDoesNotSpin(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	l := NewLongPollConfig(&signalConfig{reloaded: make(chan struct{}, 1)}, server.URL)
+	l.MinBackoff, l.MaxBackoff = 0, 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	l.Run(ctx)
+
+	// The backoff floor allows about ten polls in 100ms, a spinning loop issues thousands
+	if n := requests.Load(); n > 20 {
+		t.Errorf("Run() polled %d times in 100ms with zero backoffs, want the backoff floored", n)
+	}
+}
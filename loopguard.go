@@ -0,0 +1,108 @@
+package configdecorator
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+/*
+#########################################################################
+# Reload Loop Guard Section - Decorator detecting suspiciously frequent reloads
+#########################################################################
+*/
+
+// ErrReloadLoop is returned by LoopGuardedConfig when Fail is set and the guard triggers
+var ErrReloadLoop = errors.New("reload loop detected")
+
+// LoopGuardedConfig is a decorator that warns when more than a set number of reloads happen
+// within a window, which usually means a misconfigured watch loop is hammering Reload. The
+// guard is off until SetReloadLoopGuard configures it
+type LoopGuardedConfig struct {
+	Configurer
+	// Fail makes a reload that triggers the guard return ErrReloadLoop without reloading instead of only warning
+	Fail bool
+	// Now returns the current time and may be replaced to control the clock in tests
+	Now func() time.Time
+	// Logger receives the loop warnings when set, a nil Logger keeps the guard silent
+	Logger *log.Logger
+
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	reloads   []time.Time
+	triggered int
+	changeNotifier
+}
+
+// NewLoopGuardedConfig creates a new LoopGuardedConfig struct that decorates the next Configurer
+func NewLoopGuardedConfig(next Configurer) *LoopGuardedConfig {
+	return &LoopGuardedConfig{
+		Configurer: next,
+		Now:        time.Now,
+	}
+}
+
+// SetReloadLoopGuard makes the guard trigger on any reload that is the n+1th within window,
+// an n of 0 or less turns the guard off
+func (g *LoopGuardedConfig) SetReloadLoopGuard(n int, window time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit, g.window = n, window
+	g.reloads = nil
+}
+
+// Triggered returns how many reloads triggered the guard
+func (g *LoopGuardedConfig) Triggered() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.triggered
+}
+
+// Reload records the reload, warns when it triggers the guard and reloads the wrapped
+// configuration unless Fail is set, it implements the Configurer interface
+func (g *LoopGuardedConfig) Reload() error {
+	before := g.hooks.before(g)
+	err := g.reload()
+	g.hooks.notify(before, g, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (g *LoopGuardedConfig) reload() error {
+	if g.record() && g.Fail {
+		return ErrReloadLoop
+	}
+	return g.Configurer.Reload()
+}
+
+// record adds a reload at the current time and reports whether it triggered the guard
+func (g *LoopGuardedConfig) record() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.limit <= 0 {
+		return false
+	}
+
+	now := g.Now()
+	recent := g.reloads[:0]
+	for _, at := range g.reloads {
+		if now.Sub(at) < g.window {
+			recent = append(recent, at)
+		}
+	}
+	g.reloads = append(recent, now)
+	if len(g.reloads) <= g.limit {
+		return false
+	}
+
+	g.triggered++
+	logf(g.Logger, "Possible reload loop: %d reloads within %s, more than the %d allowed", len(g.reloads), g.window, g.limit)
+	return true
+}
+
+// Unwrap returns the Configurer wrapped by the LoopGuardedConfig decorator
+func (g *LoopGuardedConfig) Unwrap() Configurer {
+	return g.Configurer
+}
@@ -0,0 +1,75 @@
+package configdecorator
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoopGuardedConfigTriggersOnRapidReloads(t *testing.T) {
+	clock := newFakeClock()
+	inner := &countingConfig{}
+	var logs bytes.Buffer
+	g := NewLoopGuardedConfig(inner)
+	g.Now, g.Logger = clock.Now, log.New(&logs, "", 0)
+	g.SetReloadLoopGuard(3, time.Second)
+
+	for range 5 {
+		if err := g.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v, want the guard to only warn", err)
+		}
+		clock.Sleep(100 * time.Millisecond)
+	}
+	if got := g.Triggered(); got != 2 {
+		t.Errorf("Triggered() = %d, want 2 for the 4th and 5th reload", got)
+	}
+	if inner.reloads != 5 {
+		t.Errorf("inner reloads = %d, want 5", inner.reloads)
+	}
+	if !strings.Contains(logs.String(), "Possible reload loop") {
+		t.Errorf("log = %q, want a reload loop warning", logs.String())
+	}
+
+	// Reloads spread beyond the window do not trigger it
+	clock.Sleep(time.Second)
+	for range 3 {
+		clock.Sleep(time.Second)
+		if err := g.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+	}
+	if got := g.Triggered(); got != 2 {
+		t.Errorf("Triggered() = %d after slow reloads, want it unchanged", got)
+	}
+}
+
+func TestLoopGuardedConfigFail(t *testing.T) {
+	clock := newFakeClock()
+	inner := &countingConfig{}
+	g := NewLoopGuardedConfig(inner)
+	g.Now, g.Fail = clock.Now, true
+	g.SetReloadLoopGuard(1, time.Minute)
+
+	if err := g.Reload(); err != nil {
+		t.Fatalf("first Reload() error = %v", err)
+	}
+	if err := g.Reload(); !errors.Is(err, ErrReloadLoop) {
+		t.Fatalf("second Reload() error = %v, want ErrReloadLoop", err)
+	}
+	if inner.reloads != 1 {
+		t.Errorf("inner reloads = %d, want the failing reload to be skipped", inner.reloads)
+	}
+}
+
+func TestLoopGuardedConfigOffByDefault(t *testing.T) {
+	g := NewLoopGuardedConfig(&countingConfig{})
+	g.Fail = true
+	for range 10 {
+		if err := g.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v, want the guard off until SetReloadLoopGuard", err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux || darwin || freebsd
+
+package configdecorator
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+/*
+#########################################################################
+# Mmap Section - Memory mapped config file reads
+#########################################################################
+*/
+
+// mapConfigFile maps the file at path read only instead of reading it onto the heap, refusing files
+// larger than maxSize bytes, a maxSize of 0 or less uses DefaultMaxFileSize. The returned data is only valid until unmap is called.
+// Reading a page past the end of a file truncated after it was mapped raises SIGBUS, so the file
+// must only ever be replaced by rename, which leaves the mapped inode intact
+func mapConfigFile(path string, maxSize int64) (data []byte, unmap func() error, err error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading config file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading config file: %w", err)
+	}
+	if info.Size() > maxSize {
+		return nil, nil, fmt.Errorf("config file %s is %d bytes, larger than the %d byte limit", path, info.Size(), maxSize)
+	}
+
+	// An empty file cannot be mapped
+	if info.Size() == 0 {
+		return nil, func() error { return nil }, nil
+	}
+
+	data, err = syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mapping config file: %w", err)
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd)
+
+package configdecorator
+
+// mapConfigFile falls back to reading the file at path since memory mapping is not supported on this platform
+func mapConfigFile(path string, maxSize int64) (data []byte, unmap func() error, err error) {
+	data, err = readConfigFile(path, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
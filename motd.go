@@ -0,0 +1,112 @@
+package configdecorator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+/*
+#########################################################################
+# Message of the Day Config Section - Decorator for the Config struct
+#########################################################################
+*/
+
+// MessageOfTheDay is a decorator for the Config struct and adds a message of the day
+// functionality to the configuration, read the MOTD through GetMOTD when the config
+// may be reloaded concurrently
+type MessageOfTheDay struct {
+	Configurer
+	MOTD string `env:"MOTD" default:"Have a Nice Day!" flag:"motd" usage:"message of the day"`
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewMessageOfTheDay creates a new MessageOfTheDay struct that decorates the Config struct
+func NewMessageOfTheDay(config Configurer, motd string, opts ...Option) *MessageOfTheDay {
+	o := applyOptions(opts)
+	return &MessageOfTheDay{
+		Configurer: config,
+		MOTD:       motd,
+		source:     o.source,
+	}
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface
+func (m *MessageOfTheDay) Reload() error {
+	before := m.hooks.before(m)
+	err := m.reload()
+	m.hooks.notify(before, m, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (m *MessageOfTheDay) reload() error {
+	logf(m.Logger, "Reloading message of the day")
+	m.reloaded()
+
+	// Reload the base configuration
+	err := m.Configurer.Reload()
+
+	// Only take this layer's lock once the wrapped configuration has finished reloading
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Load the tagged fields from the source, the default only applies when the key is not set
+	return errors.Join(err, layerError(m, BindSource(m, m.source)))
+}
+
+// GetMOTD returns the MOTD and is safe to call while the config is reloading
+func (m *MessageOfTheDay) GetMOTD() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.MOTD
+}
+
+// MOTDCtx returns the MOTD override carried in ctx, falling back to the config MOTD
+func (m *MessageOfTheDay) MOTDCtx(ctx context.Context) string {
+	return requestOverride(ctx, "MOTD", m.GetMOTD())
+}
+
+// Clone returns a copy of the MessageOfTheDay and the chain it wraps and implements the Cloner interface
+func (m *MessageOfTheDay) Clone() Configurer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	clone := &MessageOfTheDay{
+		Configurer: cloneConfigurer(m.Configurer),
+		MOTD:       m.MOTD,
+		Logger:     m.Logger,
+		source:     m.source,
+	}
+	m.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// fields returns the current MOTD
+func (m *MessageOfTheDay) fields() []fieldValue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return []fieldValue{{"MOTD", "MOTD", m.MOTD}}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (m *MessageOfTheDay) replaceSource(replace func(Source) Source) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.source = replace(m.source)
+}
+
+// layerName returns the name the MessageOfTheDay reports its errors under
+func (m *MessageOfTheDay) layerName() string {
+	return "message of the day"
+}
+
+// Unwrap returns the Configurer wrapped by the MessageOfTheDay decorator
+func (m *MessageOfTheDay) Unwrap() Configurer {
+	return m.Configurer
+}
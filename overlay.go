@@ -0,0 +1,121 @@
+package configdecorator
+
+import (
+	"maps"
+	"sync"
+)
+
+/*
+#########################################################################
+# Layered Source Section - Layers decorator values under the chain's sources
+#########################################################################
+*/
+
+// sourcedLayer is implemented by the layers that load their fields from a Source, so that value
+// decorators such as FileConfig can layer their values into it and the layer binds them directly
+type sourcedLayer interface {
+	// replaceSource swaps the layer's Source for the one returned by replace while holding the layer's lock
+	replaceSource(replace func(Source) Source)
+}
+
+// valueDecorator is implemented by the decorators that layer their values under the chain they wrap,
+// so a Gate that is switched off can stop the wrapped layers from binding values it no longer applies
+type valueDecorator interface {
+	// unlayer clears the layered values, the decorator's next reload layers them again
+	unlayer()
+}
+
+// layeredSource is the Source of a layer wrapped by value decorators. A key set in the layer's
+// own source always wins, otherwise the first of layers that has the key provides it, layers
+// are ordered from the most recently created decorator to the oldest
+type layeredSource struct {
+	base   Source
+	layers []Source
+}
+
+// Get looks up key in the base source and then in each layer, it implements the Source interface
+func (l *layeredSource) Get(key string) (string, bool) {
+	if v, ok := sourceOrEnv(l.base).Get(key); ok {
+		return v, true
+	}
+	for _, layer := range l.layers {
+		if v, ok := layer.Get(key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// layerSource layers values into the Source of every layer of the chain below c, ahead of the
+// values of decorators created before it so the outermost decorator wins
+func layerSource(c Configurer, values Source) {
+	eachSourcedLayer(c, func(s Source) Source {
+		layered, ok := s.(*layeredSource)
+		if !ok {
+			return &layeredSource{base: s, layers: []Source{values}}
+		}
+		return &layeredSource{base: layered.base, layers: append([]Source{values}, layered.layers...)}
+	})
+}
+
+// relayerSource replaces old with values in the Sources of the chain below c, a cloned decorator
+// uses it so the cloned layers read the clone's values instead of the original's
+func relayerSource(c Configurer, old, values Source) {
+	eachSourcedLayer(c, func(s Source) Source {
+		layered, ok := s.(*layeredSource)
+		if !ok {
+			return s
+		}
+		layers := make([]Source, len(layered.layers))
+		for i, layer := range layered.layers {
+			if layer == old {
+				layer = values
+			}
+			layers[i] = layer
+		}
+		return &layeredSource{base: layered.base, layers: layers}
+	})
+}
+
+// eachSourcedLayer calls replaceSource with replace on every layer of the chain below c that reads a Source
+func eachSourcedLayer(c Configurer, replace func(Source) Source) {
+	for c != nil {
+		if layer, ok := c.(sourcedLayer); ok {
+			layer.replaceSource(replace)
+		}
+
+		u, ok := c.(Unwrapper)
+		if !ok {
+			return
+		}
+		c = u.Unwrap()
+	}
+}
+
+// valueLayer holds the values a decorator such as FileConfig layers under the chain it wraps
+type valueLayer struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// Get looks up key in the values and implements the Source interface
+func (v *valueLayer) Get(key string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	value, ok := v.values[key]
+	return value, ok
+}
+
+// set replaces the values, layers below pick them up on their next reload
+func (v *valueLayer) set(values map[string]string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values = values
+}
+
+// copyValues returns a copy of the current values
+func (v *valueLayer) copyValues() map[string]string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return maps.Clone(v.values)
+}
@@ -0,0 +1,41 @@
+//go:build (linux || darwin || freebsd) && cgo
+
+package configdecorator
+
+import (
+	"fmt"
+	"plugin"
+)
+
+/*
+#########################################################################
+# Plugin Section - Config layers loaded from Go plugins
+#########################################################################
+*/
+
+// PluginSourceFunc is the signature of the NewSource symbol a config plugin must export
+type PluginSourceFunc = func(params map[string]string, next Configurer) (Configurer, error)
+
+// RegisterPlugin opens the Go plugin at path and registers its exported NewSource function as
+// the layer called name, params are passed to NewSource each time the layer is built. The plugin
+// must be built against the same version of this package so its Configurer type matches
+func (r *Registry) RegisterPlugin(name, path string, params map[string]string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening config plugin %s: %w", path, err)
+	}
+
+	sym, err := p.Lookup("NewSource")
+	if err != nil {
+		return fmt.Errorf("config plugin %s: %w", path, err)
+	}
+	newSource, ok := sym.(PluginSourceFunc)
+	if !ok {
+		return fmt.Errorf("config plugin %s: NewSource has type %T, want %T", path, sym, PluginSourceFunc(nil))
+	}
+
+	r.RegisterLayer(name, func(next Configurer) (Configurer, error) {
+		return newSource(params, next)
+	})
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build (linux || darwin || freebsd) && cgo
+
+package configdecorator
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterPluginOpenError(t *testing.T) {
+	r := NewRegistry()
+	path := filepath.Join(t.TempDir(), "missing.so")
+	if err := r.RegisterPlugin("remote", path, nil); err == nil {
+		t.Fatal("RegisterPlugin() error = nil, want the open error")
+	}
+	if _, ok := r.Layer("remote"); ok {
+		t.Error("RegisterPlugin() registered a layer for a plugin that failed to open")
+	}
+}
+
+func TestRegisterPluginNotAPlugin(t *testing.T) {
+	r := NewRegistry()
+	path := writeFile(t, t.TempDir(), "fake.so", "not a shared object")
+	if err := r.RegisterPlugin("remote", path, nil); err == nil {
+		t.Fatal("RegisterPlugin() error = nil, want an error for a file that is not a plugin")
+	}
+}
+
+// buildGo runs go build with args and skips the test where the toolchain or plugins are unavailable
+func buildGo(t *testing.T, args ...string) {
+	t.Helper()
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+	out, err := exec.Command(goTool, append([]string{"build"}, args...)...).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "not supported") {
+			t.Skipf("go build %s: %s", strings.Join(args, " "), out)
+		}
+		t.Fatalf("go build %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestRegisterPluginBuildsLayer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a plugin")
+	}
+
+	// Build the host as well, a plugin only opens in a binary built the same way as it
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "source.so")
+	host := filepath.Join(dir, "pluginhost")
+	buildGo(t, "-buildmode=plugin", "-o", plugin, "./testdata/plugin")
+	buildGo(t, "-o", host, "./testdata/pluginhost")
+
+	out, err := exec.Command(host, plugin).CombinedOutput()
+	if err != nil {
+		t.Fatalf("pluginhost: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "hello from the plugin" {
+		t.Errorf("plugin layer MOTD = %q, want the motd param", got)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !((linux || darwin || freebsd) && cgo)
+
+package configdecorator
+
+import "errors"
+
+// ErrPluginUnsupported is returned by RegisterPlugin on platforms without Go plugin support
+var ErrPluginUnsupported = errors.New("config plugins are not supported on this platform")
+
+// PluginSourceFunc is the signature of the NewSource symbol a config plugin must export
+type PluginSourceFunc = func(params map[string]string, next Configurer) (Configurer, error)
+
+// RegisterPlugin always returns ErrPluginUnsupported since Go plugins are not supported on this platform
+func (r *Registry) RegisterPlugin(name, path string, params map[string]string) error {
+	return ErrPluginUnsupported
+}
@@ -0,0 +1,18 @@
+//go:build !((linux || darwin || freebsd) && cgo)
+
+package configdecorator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterPluginUnsupported(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterPlugin("remote", "remote.so", nil); !errors.Is(err, ErrPluginUnsupported) {
+		t.Fatalf("RegisterPlugin() error = %v, want ErrPluginUnsupported", err)
+	}
+	if _, ok := r.Layer("remote"); ok {
+		t.Error("RegisterPlugin() registered a layer on an unsupported platform")
+	}
+}
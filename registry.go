@@ -0,0 +1,123 @@
+package configdecorator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+/*
+#########################################################################
+# Registry Section - Shares named Configurer instances across a process
+#########################################################################
+*/
+
+// LayerFactory builds a config layer decorating next, next is nil for the first layer of a chain
+type LayerFactory func(next Configurer) (Configurer, error)
+
+// Registry holds named Configurer instances so independent parts of an application
+// share a single instance of the same logical configuration, and named layer factories
+// used to assemble chains from a list of layer names
+type Registry struct {
+	mu      sync.Mutex
+	configs map[string]Configurer
+	layers  map[string]LayerFactory
+}
+
+// NewRegistry creates a new empty Registry struct
+func NewRegistry() *Registry {
+	return &Registry{
+		configs: make(map[string]Configurer),
+		layers:  make(map[string]LayerFactory),
+	}
+}
+
+// DefaultRegistry is the process-global Registry used by GetOrCreate and ResetRegistry
+var DefaultRegistry = NewRegistry()
+
+// GetOrCreate returns the Configurer registered under name, calling factory to create
+// and register it the first time the name is requested. The factory runs without the registry
+// locked so it may use the registry itself, callers racing on a new name may each run it but
+// all of them get the instance registered first
+func (r *Registry) GetOrCreate(name string, factory func() Configurer) Configurer {
+	r.mu.Lock()
+	c, ok := r.configs[name]
+	r.mu.Unlock()
+	if ok {
+		return c
+	}
+
+	created := factory()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.configs[name]; ok {
+		return c
+	}
+	r.configs[name] = created
+	return created
+}
+
+// RegisterLayer registers the factory for the layer called name, replacing any previous one
+func (r *Registry) RegisterLayer(name string, factory LayerFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.layers[name] = factory
+}
+
+// Layer returns the factory registered for the layer called name
+func (r *Registry) Layer(name string) (LayerFactory, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	factory, ok := r.layers[name]
+	return factory, ok
+}
+
+// Reset removes every Configurer and layer factory from the registry, this is mainly useful in tests
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs = make(map[string]Configurer)
+	r.layers = make(map[string]LayerFactory)
+}
+
+// GetOrCreate returns the shared Configurer registered under name in the DefaultRegistry
+func GetOrCreate(name string, factory func() Configurer) Configurer {
+	return DefaultRegistry.GetOrCreate(name, factory)
+}
+
+// ResetRegistry clears the DefaultRegistry
+func ResetRegistry() {
+	DefaultRegistry.Reset()
+}
+
+// BuildFromEnv assembles a chain from the comma separated layer names in the varName
+// environment variable, e.g. CONFIG_LAYERS=base,database,motd. The first name is the
+// innermost layer and each following layer decorates the one before it, the outermost
+// layer is returned. Unknown layer names and a nil registry return an error
+func BuildFromEnv(varName string, registry *Registry) (Configurer, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("building config from %s: nil registry", varName)
+	}
+	spec := os.Getenv(varName)
+	if spec == "" {
+		return nil, fmt.Errorf("%s is not set", varName)
+	}
+
+	var chain Configurer
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		factory, ok := registry.Layer(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown config layer %q in %s", name, varName)
+		}
+
+		layer, err := factory(chain)
+		if err != nil {
+			return nil, fmt.Errorf("building config layer %q: %w", name, err)
+		}
+		chain = layer
+	}
+	return chain, nil
+}
@@ -0,0 +1,123 @@
+package configdecorator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRegistryGetOrCreateSharesInstance(t *testing.T) {
+	r := NewRegistry()
+	calls := 0
+	factory := func() Configurer {
+		calls++
+		return NewConfig("", "", WithSource(MapSource{}))
+	}
+
+	first := r.GetOrCreate("app", factory)
+	second := r.GetOrCreate("app", factory)
+	if first != second {
+		t.Error("GetOrCreate() returned different instances for the same name")
+	}
+	if calls != 1 {
+		t.Errorf("factory calls = %d, want 1", calls)
+	}
+	if other := r.GetOrCreate("other", factory); other == first {
+		t.Error("GetOrCreate() shared an instance across names")
+	}
+}
+
+func TestRegistryGetOrCreateConcurrent(t *testing.T) {
+	r := NewRegistry()
+	got := make([]Configurer, 8)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = r.GetOrCreate("app", func() Configurer {
+				return NewConfig("", "", WithSource(MapSource{}))
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i := range got {
+		if got[i] != got[0] {
+			t.Fatalf("caller %d got a different instance", i)
+		}
+	}
+}
+
+func TestRegistryFactoryMayUseRegistry(t *testing.T) {
+	r := NewRegistry()
+	base := r.GetOrCreate("database", func() Configurer {
+		inner := r.GetOrCreate("base", func() Configurer {
+			return NewConfig("", "", WithSource(MapSource{}))
+		})
+		return NewDatabaseConfig(inner, "", "", WithSource(MapSource{}))
+	})
+
+	if inner := base.(*DatabaseConfig).Unwrap(); inner != r.GetOrCreate("base", nil) {
+		t.Error("nested GetOrCreate() did not register the inner instance")
+	}
+}
+
+func TestBuildFromEnv(t *testing.T) {
+	r := NewRegistry()
+	src := MapSource{}
+	r.RegisterLayer("base", func(Configurer) (Configurer, error) {
+		return NewConfig("", "", WithSource(src)), nil
+	})
+	r.RegisterLayer("database", func(next Configurer) (Configurer, error) {
+		return NewDatabaseConfig(next, "", "", WithSource(src)), nil
+	})
+	r.RegisterLayer("motd", func(next Configurer) (Configurer, error) {
+		return NewMessageOfTheDay(next, "", WithSource(src)), nil
+	})
+	t.Setenv("CONFIG_LAYERS", "base, database,motd")
+
+	chain, err := BuildFromEnv("CONFIG_LAYERS", r)
+	if err != nil {
+		t.Fatalf("BuildFromEnv() error = %v", err)
+	}
+	motd, ok := chain.(*MessageOfTheDay)
+	if !ok {
+		t.Fatalf("outermost layer = %T, want *MessageOfTheDay", chain)
+	}
+	db, ok := motd.Unwrap().(*DatabaseConfig)
+	if !ok {
+		t.Fatalf("middle layer = %T, want *DatabaseConfig", motd.Unwrap())
+	}
+	if _, ok := db.Unwrap().(*Config); !ok {
+		t.Errorf("innermost layer = %T, want *Config", db.Unwrap())
+	}
+}
+
+func TestBuildFromEnvErrors(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterLayer("base", func(Configurer) (Configurer, error) {
+		return NewConfig("", ""), nil
+	})
+	r.RegisterLayer("broken", func(Configurer) (Configurer, error) {
+		return nil, errors.New("broken layer")
+	})
+
+	for _, tt := range []struct {
+		name     string
+		spec     string
+		registry *Registry
+	}{
+		{name: "unset", registry: r},
+		{name: "unknown layer", spec: "base,missing", registry: r},
+		{name: "factory error", spec: "base,broken", registry: r},
+		{name: "nil registry", spec: "base"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_LAYERS", tt.spec)
+			if _, err := BuildFromEnv("CONFIG_LAYERS", tt.registry); err == nil {
+				t.Error("BuildFromEnv() error = nil, want an error")
+			}
+		})
+	}
+}
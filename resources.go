@@ -0,0 +1,156 @@
+package configdecorator
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+#########################################################################
+# Resource Aware Config Section - Decorator with container aware defaults
+#########################################################################
+*/
+
+// ResourceAwareConfig is a decorator adding a MaxWorkers setting. When MAX_WORKERS is not set
+// it defaults to the number of CPUs available to the process, honouring a cgroup CPU quota
+// when running in a container. Read it through GetMaxWorkers when the config may be reloaded concurrently
+type ResourceAwareConfig struct {
+	Configurer
+	MaxWorkers int `env:"MAX_WORKERS"`
+	// DetectCPUs returns the CPU limit used as the MaxWorkers default and may be replaced in tests
+	DetectCPUs func() int
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewResourceAwareConfig creates a new ResourceAwareConfig struct that decorates the next Configurer
+func NewResourceAwareConfig(next Configurer, opts ...Option) *ResourceAwareConfig {
+	o := applyOptions(opts)
+	return &ResourceAwareConfig{
+		Configurer: next,
+		DetectCPUs: DetectCPULimit,
+		source:     o.source,
+	}
+}
+
+// Reload reloads the configuration from the source, the environment by default, and implements the Configurer interface
+func (r *ResourceAwareConfig) Reload() error {
+	before := r.hooks.before(r)
+	err := r.reload()
+	r.hooks.notify(before, r, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (r *ResourceAwareConfig) reload() error {
+	logf(r.Logger, "Reloading resource aware config")
+	r.reloaded()
+
+	// Reload the base configuration
+	err := r.Configurer.Reload()
+
+	// Only take this layer's lock once the wrapped configuration has finished reloading
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Start from the detected limit, an explicitly set MAX_WORKERS then overrides it
+	r.MaxWorkers = r.DetectCPUs()
+	return errors.Join(err, layerError(r, BindSource(r, r.source)))
+}
+
+// GetMaxWorkers returns the MaxWorkers and is safe to call while the config is reloading
+func (r *ResourceAwareConfig) GetMaxWorkers() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.MaxWorkers
+}
+
+// Clone returns a copy of the ResourceAwareConfig and the chain it wraps and implements the Cloner interface
+func (r *ResourceAwareConfig) Clone() Configurer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := &ResourceAwareConfig{
+		Configurer: cloneConfigurer(r.Configurer),
+		MaxWorkers: r.MaxWorkers,
+		DetectCPUs: r.DetectCPUs,
+		Logger:     r.Logger,
+		source:     r.source,
+	}
+	r.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// fields returns the current MaxWorkers
+func (r *ResourceAwareConfig) fields() []fieldValue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return []fieldValue{{"MaxWorkers", "MAX_WORKERS", strconv.Itoa(r.MaxWorkers)}}
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (r *ResourceAwareConfig) replaceSource(replace func(Source) Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source = replace(r.source)
+}
+
+// layerName returns the name the ResourceAwareConfig reports its errors under
+func (r *ResourceAwareConfig) layerName() string {
+	return "resource aware config"
+}
+
+// Unwrap returns the Configurer wrapped by the ResourceAwareConfig decorator
+func (r *ResourceAwareConfig) Unwrap() Configurer {
+	return r.Configurer
+}
+
+// DetectCPULimit returns the number of CPUs available to the process, the cgroup CPU quota
+// when one is set and lower than runtime.NumCPU, and runtime.NumCPU otherwise
+func DetectCPULimit() int {
+	return detectCPULimit(os.DirFS("/"), runtime.NumCPU())
+}
+
+// detectCPULimit reads the cgroup v2 cpu.max, or the cgroup v1 CFS quota and period, from fsys
+// and returns the quota rounded up to whole CPUs. numCPU is returned when no quota is found
+func detectCPULimit(fsys fs.FS, numCPU int) int {
+	var quota, period float64
+	if data, err := fs.ReadFile(fsys, "sys/fs/cgroup/cpu.max"); err == nil {
+		// cgroup v2 writes "<quota> <period>" or "max <period>" when unlimited
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 {
+			quota, _ = strconv.ParseFloat(fields[0], 64)
+			period, _ = strconv.ParseFloat(fields[1], 64)
+		}
+	} else {
+		// cgroup v1 uses separate files and a quota of -1 when unlimited
+		quota = readCgroupFloat(fsys, "sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+		period = readCgroupFloat(fsys, "sys/fs/cgroup/cpu/cpu.cfs_period_us")
+	}
+
+	if quota <= 0 || period <= 0 {
+		return numCPU
+	}
+	limit := int(math.Ceil(quota / period))
+	return max(1, min(limit, numCPU))
+}
+
+// readCgroupFloat reads a single number from a cgroup file, returning 0 when it is missing or invalid
+func readCgroupFloat(fsys fs.FS, name string) float64 {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	return n
+}
@@ -0,0 +1,60 @@
+package configdecorator
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectCPULimit(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		files map[string]string
+		want  int
+	}{
+		{name: "no cgroup", want: 8},
+		{name: "v2 quota", files: map[string]string{"sys/fs/cgroup/cpu.max": "150000 100000\n"}, want: 2},
+		{name: "v2 unlimited", files: map[string]string{"sys/fs/cgroup/cpu.max": "max 100000\n"}, want: 8},
+		{name: "v2 quota above host", files: map[string]string{"sys/fs/cgroup/cpu.max": "1600000 100000"}, want: 8},
+		{name: "v2 tiny quota", files: map[string]string{"sys/fs/cgroup/cpu.max": "1000 100000"}, want: 1},
+		{name: "v2 malformed", files: map[string]string{"sys/fs/cgroup/cpu.max": "garbage"}, want: 8},
+		{name: "v1 quota", files: map[string]string{
+			"sys/fs/cgroup/cpu/cpu.cfs_quota_us":  "300000\n",
+			"sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n",
+		}, want: 3},
+		{name: "v1 unlimited", files: map[string]string{
+			"sys/fs/cgroup/cpu/cpu.cfs_quota_us":  "-1\n",
+			"sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n",
+		}, want: 8},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, data := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(data)}
+			}
+			if got := detectCPULimit(fsys, 8); got != tt.want {
+				t.Errorf("detectCPULimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceAwareConfigMaxWorkers(t *testing.T) {
+	src := MapSource{}
+	r := NewResourceAwareConfig(&countingConfig{}, WithSource(src))
+	r.DetectCPUs = func() int { return 3 }
+
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := r.GetMaxWorkers(); got != 3 {
+		t.Errorf("MaxWorkers = %d, want the detected limit", got)
+	}
+
+	src["MAX_WORKERS"] = "12"
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := r.GetMaxWorkers(); got != 12 {
+		t.Errorf("MaxWorkers = %d, want MAX_WORKERS to override the detected limit", got)
+	}
+}
@@ -0,0 +1,111 @@
+package configdecorator
+
+import (
+	"log"
+	"slices"
+	"sync"
+)
+
+/*
+#########################################################################
+# Shadow Section - Compares a candidate chain against the live one
+#########################################################################
+*/
+
+// Shadow reloads a primary chain and a shadow chain side by side for migrating between sources.
+// The primary is authoritative and is the chain Shadow wraps, the shadow is only loaded to record
+// every field where it disputes the primary, so a new source can be observed before switching to it
+type Shadow struct {
+	Configurer
+	Shadow Configurer
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	diffs  []Change
+	mu     sync.RWMutex
+	changeNotifier
+}
+
+// NewShadow creates a new Shadow struct that serves primary and compares it with shadow on every reload
+func NewShadow(primary, shadow Configurer) *Shadow {
+	return &Shadow{
+		Configurer: primary,
+		Shadow:     shadow,
+	}
+}
+
+// Reload reloads the primary and the shadow chain and implements the Configurer interface. Only the
+// primary's error is returned, a failing shadow is logged and never affects the primary
+func (s *Shadow) Reload() error {
+	before := s.hooks.before(s)
+	err := s.reload()
+	s.hooks.notify(before, s, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (s *Shadow) reload() error {
+	err := s.Configurer.Reload()
+
+	if shadowErr := s.Shadow.Reload(); shadowErr != nil {
+		logf(s.Logger, "Shadow config failed to reload: %v", shadowErr)
+	}
+
+	shadowFields := snapshot(s.Shadow)
+	shadowed := make(map[string]string, len(shadowFields))
+	for _, field := range shadowFields {
+		shadowed[field.Field] = field.Value
+	}
+
+	// Old is the primary value and New the shadow value, a field the shadow has no layer for is reported with an empty New
+	var diffs []Change
+	primary := make(map[string]bool)
+	for _, field := range snapshot(s.Configurer) {
+		primary[field.Field] = true
+		if value := shadowed[field.Field]; value != field.Value {
+			logf(s.Logger, "Shadow config differs on %s: primary %q, shadow %q", field.Field, field.Value, value)
+			diffs = append(diffs, Change{Field: field.Field, Old: field.Value, New: value})
+		}
+	}
+
+	// A field only the shadow has a layer for is reported with an empty Old
+	for _, field := range shadowFields {
+		if !primary[field.Field] {
+			logf(s.Logger, "Shadow config has %s which the primary lacks: shadow %q", field.Field, field.Value)
+			diffs = append(diffs, Change{Field: field.Field, New: field.Value})
+			primary[field.Field] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.diffs = diffs
+	s.mu.Unlock()
+	return err
+}
+
+// ShadowDiffs returns the fields where the shadow differed from the primary on the last reload,
+// Old holds the primary value and New the shadow value. Fields only one of the chains has a layer
+// for are included with the other side empty, primary fields first
+func (s *Shadow) ShadowDiffs() []Change {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Change(nil), s.diffs...)
+}
+
+// Clone returns a copy of the Shadow and both chains and implements the Cloner interface
+func (s *Shadow) Clone() Configurer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	clone := &Shadow{
+		Configurer: cloneConfigurer(s.Configurer),
+		Shadow:     cloneConfigurer(s.Shadow),
+		Logger:     s.Logger,
+		diffs:      slices.Clone(s.diffs),
+	}
+	s.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// Unwrap returns the primary Configurer wrapped by the Shadow
+func (s *Shadow) Unwrap() Configurer {
+	return s.Configurer
+}
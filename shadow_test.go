@@ -0,0 +1,47 @@
+package configdecorator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestShadowPrimaryWinsAndRecordsDiffs(t *testing.T) {
+	primarySrc := MapSource{"PORT": "9000", "DB_PORT": "27017"}
+	shadowSrc := MapSource{"PORT": "9001", "MOTD": "from shadow"}
+	primaryBase := NewConfig("", "", WithSource(primarySrc))
+	primary := NewDatabaseConfig(primaryBase, "", "", WithSource(primarySrc))
+	shadow := NewMessageOfTheDay(NewConfig("", "", WithSource(shadowSrc)), "", WithSource(shadowSrc))
+	s := NewShadow(primary, shadow)
+
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := primaryBase.GetPort(); got != "9000" {
+		t.Errorf("Port = %q, want the primary value", got)
+	}
+
+	want := []Change{
+		{Field: "DBAddress", Old: "http://localhost"},
+		{Field: "DBPort", Old: "27017"},
+		{Field: "Port", Old: "9000", New: "9001"},
+		{Field: "MOTD", New: "from shadow"},
+	}
+	if got := s.ShadowDiffs(); !slices.Equal(got, want) {
+		t.Errorf("ShadowDiffs() = %v, want %v", got, want)
+	}
+}
+
+func TestShadowFailureDoesNotAffectPrimary(t *testing.T) {
+	src := MapSource{"PORT": "9000"}
+	s := NewShadow(NewConfig("", "", WithSource(src)), &failingConfig{err: errors.New("shadow down")})
+	if err := s.Reload(); err != nil {
+		t.Errorf("Reload() error = %v, want the shadow failure ignored", err)
+	}
+
+	errPrimary := errors.New("primary down")
+	s = NewShadow(&failingConfig{err: errPrimary}, NewConfig("", "", WithSource(src)))
+	if err := s.Reload(); !errors.Is(err, errPrimary) {
+		t.Errorf("Reload() error = %v, want the primary error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package configdecorator
+
+import "os"
+
+/*
+#########################################################################
+# Source Section - Where the configs read their values from
+#########################################################################
+*/
+
+// Source provides the raw values the configs load on Reload, the ok result distinguishes a
+// key that is set to an empty string from a key that is not set at all
+type Source interface {
+	Get(key string) (value string, ok bool)
+}
+
+// EnvSource is a Source backed by the process environment, it is the default Source
+type EnvSource struct{}
+
+// Get looks up key in the process environment and implements the Source interface
+func (EnvSource) Get(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// MapSource is a Source backed by a map, it is mainly useful in tests. Like any map it must
+// not be modified while a config may be reloading from it, e.g. from Watch
+type MapSource map[string]string
+
+// Get looks up key in the map and implements the Source interface
+func (m MapSource) Get(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// options holds the optional settings shared by the config constructors
+type options struct {
+	source Source
+}
+
+// Option configures optional settings of a config when passed to its constructor
+type Option func(*options)
+
+// WithSource sets the Source a config reads its values from instead of the environment
+func WithSource(source Source) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// applyOptions applies opts over the default settings
+func applyOptions(opts []Option) options {
+	o := options{source: EnvSource{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// sourceOrEnv returns source, or an EnvSource for configs built without a constructor
+func sourceOrEnv(source Source) Source {
+	if source == nil {
+		return EnvSource{}
+	}
+	return source
+}
+
+// lookup reads key from source, returning def only when the key is not set
+func lookup(source Source, key string, def string) string {
+	if v, ok := sourceOrEnv(source).Get(key); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,43 @@
+package configdecorator
+
+import "testing"
+
+func TestMapSourceEmptyValueIsSet(t *testing.T) {
+	src := MapSource{"MOTD": ""}
+	if v, ok := src.Get("MOTD"); !ok || v != "" {
+		t.Errorf("Get(MOTD) = %q, %v, want an empty value that is set", v, ok)
+	}
+	if _, ok := src.Get("PORT"); ok {
+		t.Error("Get(PORT) ok = true, want false for an unset key")
+	}
+}
+
+func TestEmptyValueOverridesDefault(t *testing.T) {
+	src := MapSource{"MOTD": ""}
+	motd := NewMessageOfTheDay(NewConfig("", "", WithSource(src)), "", WithSource(src))
+	if err := motd.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := motd.GetMOTD(); got != "" {
+		t.Errorf("MOTD = %q, want the empty value rather than the default", got)
+	}
+	if got := motd.Unwrap().(*Config).GetPort(); got != "8081" {
+		t.Errorf("Port = %q, want the default for an unset key", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	src := MapSource{"SET": "value", "EMPTY": ""}
+	for _, tt := range []struct {
+		key, want string
+	}{
+		{key: "SET", want: "value"},
+		{key: "EMPTY", want: ""},
+		{key: "UNSET", want: "default"},
+	} {
+		if got := lookup(src, tt.key, "default"); got != tt.want {
+			t.Errorf("lookup(%s) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+#########################################################################
+# Staleness Section - Decorator reporting config that stopped refreshing
+#########################################################################
+*/
+
+// ErrStale is returned by FreshnessConfig.EnsureFresh when the last successful reload is too old
+var ErrStale = errors.New("config is stale")
+
+// FreshnessConfig is a decorator that tracks when the wrapped configuration last reloaded
+// successfully, so a watch loop that silently stopped refreshing can be caught with EnsureFresh
+type FreshnessConfig struct {
+	Configurer
+	// Now returns the current time and may be replaced to control the clock in tests
+	Now func() time.Time
+
+	mu           sync.Mutex
+	maxStaleness time.Duration
+	lastSuccess  time.Time
+	changeNotifier
+}
+
+// NewFreshnessConfig creates a new FreshnessConfig struct that decorates the next Configurer
+// and reports it stale once its last successful reload is older than maxStaleness
+func NewFreshnessConfig(next Configurer, maxStaleness time.Duration) *FreshnessConfig {
+	return &FreshnessConfig{
+		Configurer:   next,
+		Now:          time.Now,
+		maxStaleness: maxStaleness,
+	}
+}
+
+// SetMaxStaleness sets how old the last successful reload may be before EnsureFresh fails, 0 disables the check
+func (f *FreshnessConfig) SetMaxStaleness(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.maxStaleness = d
+}
+
+// Reload reloads the wrapped configuration and implements the Configurer interface,
+// only a reload that returns no error counts as refreshing the config
+func (f *FreshnessConfig) Reload() error {
+	before := f.hooks.before(f)
+	err := f.reload()
+	f.hooks.notify(before, f, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (f *FreshnessConfig) reload() error {
+	err := f.Configurer.Reload()
+	if err == nil {
+		f.mu.Lock()
+		f.lastSuccess = f.Now()
+		f.mu.Unlock()
+	}
+	return err
+}
+
+// LastSuccess returns the time of the last successful reload, the zero time before the first one
+func (f *FreshnessConfig) LastSuccess() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastSuccess
+}
+
+// EnsureFresh returns an error wrapping ErrStale when the config never loaded successfully
+// or its last successful reload is older than the max staleness
+func (f *FreshnessConfig) EnsureFresh() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.maxStaleness <= 0 {
+		return nil
+	}
+	if f.lastSuccess.IsZero() {
+		return fmt.Errorf("%w: never loaded successfully", ErrStale)
+	}
+	if age := f.Now().Sub(f.lastSuccess); age > f.maxStaleness {
+		return fmt.Errorf("%w: last successful reload %s ago, max %s", ErrStale, age.Round(time.Millisecond), f.maxStaleness)
+	}
+	return nil
+}
+
+// Unwrap returns the Configurer wrapped by the FreshnessConfig decorator
+func (f *FreshnessConfig) Unwrap() Configurer {
+	return f.Configurer
+}
@@ -0,0 +1,53 @@
+package configdecorator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFreshnessConfigStaleness(t *testing.T) {
+	clock := newFakeClock()
+	inner := &failingConfig{}
+	f := NewFreshnessConfig(inner, time.Minute)
+	f.Now = clock.Now
+
+	if err := f.EnsureFresh(); !errors.Is(err, ErrStale) {
+		t.Errorf("EnsureFresh() before any reload = %v, want ErrStale", err)
+	}
+
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	clock.Sleep(time.Minute)
+	if err := f.EnsureFresh(); err != nil {
+		t.Errorf("EnsureFresh() at the max staleness = %v, want nil", err)
+	}
+
+	// Failed reloads do not refresh the config
+	inner.err = errors.New("source down")
+	clock.Sleep(time.Second)
+	if err := f.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the source error")
+	}
+	if err := f.EnsureFresh(); !errors.Is(err, ErrStale) {
+		t.Errorf("EnsureFresh() after a failed reload = %v, want ErrStale", err)
+	}
+	if got, want := f.LastSuccess(), clock.now.Add(-61*time.Second); !got.Equal(want) {
+		t.Errorf("LastSuccess() = %v, want %v", got, want)
+	}
+
+	inner.err = nil
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if err := f.EnsureFresh(); err != nil {
+		t.Errorf("EnsureFresh() after a successful reload = %v, want nil", err)
+	}
+
+	f.SetMaxStaleness(0)
+	clock.Sleep(time.Hour)
+	if err := f.EnsureFresh(); err != nil {
+		t.Errorf("EnsureFresh() with the check disabled = %v, want nil", err)
+	}
+}
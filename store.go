@@ -0,0 +1,107 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+#########################################################################
+# Store Section - Lock free access to an immutable decorator chain
+#########################################################################
+*/
+
+// ErrReloadNotConfirmed is returned by Store.Reload when the confirm func rejects the changes
+var ErrReloadNotConfirmed = errors.New("reload not confirmed")
+
+// Store holds a decorator chain behind an atomic pointer so readers never lock. Reload builds
+// a fresh clone of the chain, reloads it and only swaps it in when the reload succeeds, a
+// chain handed out by Load is never modified afterwards and must be treated as read only
+type Store struct {
+	mu      sync.Mutex
+	current atomic.Pointer[Configurer]
+	confirm func(map[string]Change) bool
+}
+
+// NewStore creates a new Store struct holding root. Every layer of the chain must implement Cloner,
+// a layer that does not would be shared with the clone and reloaded under readers that still hold
+// it, so Reload refuses such a chain. Stateful wrappers such as ThrottledConfig wrap the Store instead
+func NewStore(root Configurer) *Store {
+	s := &Store{}
+	s.current.Store(&root)
+	return s
+}
+
+// Load returns the current chain without locking
+func (s *Store) Load() Configurer {
+	return *s.current.Load()
+}
+
+// Reload reloads a fresh clone of the current chain and atomically swaps it in on success,
+// a failed reload leaves the current chain untouched. OnChange callbacks of the chain run once the
+// fresh chain is swapped in, diffed against the chain it replaced. It implements the Configurer interface
+func (s *Store) Reload() error {
+	// Serialize writers so concurrent reloads do not clone the same chain twice
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.Load()
+	if err := requireCloners(current); err != nil {
+		return err
+	}
+
+	// The clone carries the OnChange callbacks of the current chain, hold them back until the
+	// fresh chain is swapped in so a failed or rejected reload notifies nobody
+	fresh := current.(Cloner).Clone()
+	muteHooks(fresh, true)
+	err := fresh.Reload()
+	muteHooks(fresh, false)
+	if err != nil {
+		return err
+	}
+
+	// Ask before swapping in a chain whose values differ from the current one
+	if s.confirm != nil {
+		changes := diffSnapshots(snapshot(current), snapshot(fresh))
+		if len(changes) > 0 {
+			diff := make(map[string]Change, len(changes))
+			for _, change := range changes {
+				diff[change.Field] = change
+			}
+			if !s.confirm(diff) {
+				return ErrReloadNotConfirmed
+			}
+		}
+	}
+
+	s.current.Store(&fresh)
+	notifyChain(current, fresh)
+	return nil
+}
+
+// SetConfirmFunc sets fn to be consulted with the changed fields, keyed by field name, after a
+// reload succeeds but before it is swapped in. Returning false discards the reloaded chain and
+// Reload returns ErrReloadNotConfirmed, a reload that changes nothing is applied without asking
+func (s *Store) SetConfirmFunc(fn func(diff map[string]Change) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confirm = fn
+}
+
+// requireCloners returns an error naming the first layer of the chain below c that does not implement Cloner
+func requireCloners(c Configurer) error {
+	for c != nil {
+		if _, ok := c.(Cloner); !ok {
+			return fmt.Errorf("store: %T does not implement Cloner", c)
+		}
+
+		u, ok := c.(Unwrapper)
+		if !ok {
+			return nil
+		}
+		c = u.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// storeValues unwraps the FileConfig chain held by a Store to its base Config
+func storeValues(t *testing.T, s *Store) (*FileConfig, *Config) {
+	t.Helper()
+	f, ok := s.Load().(*FileConfig)
+	if !ok {
+		t.Fatalf("Load() = %T, want *FileConfig", s.Load())
+	}
+	return f, f.Unwrap().(*Config)
+}
+
+func TestStoreLoadDuringReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+	s := NewStore(NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path))
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, c := storeValues(t, s)
+				if c.Port != "9000" && c.Port != "9001" {
+					t.Errorf("Port = %q, want 9000 or 9001", c.Port)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 50 {
+		writeFile(t, dir, "config.json", fmt.Sprintf(`{"PORT": "%d"}`, 9000+i%2))
+		if err := s.Reload(); err != nil {
+			t.Errorf("Reload() error = %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestStoreReloadDoesNotShareLayers(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+	s := NewStore(NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path))
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	oldFile, oldConfig := storeValues(t, s)
+
+	writeFile(t, dir, "config.json", `{"PORT": "9001"}`)
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	newFile, newConfig := storeValues(t, s)
+
+	if newFile == oldFile || newConfig == oldConfig {
+		t.Fatal("Reload() swapped in a chain sharing layers with the previous one")
+	}
+	if oldConfig.Port != "9000" {
+		t.Errorf("previous chain Port = %q, want 9000", oldConfig.Port)
+	}
+	if newConfig.Port != "9001" {
+		t.Errorf("current chain Port = %q, want 9001", newConfig.Port)
+	}
+}
+
+func TestStoreFailedReloadKeepsCurrentChain(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+	s := NewStore(NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path))
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	before := s.Load()
+
+	writeFile(t, dir, "config.json", `{not json`)
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the parse error")
+	}
+	if s.Load() != before {
+		t.Error("failed Reload() swapped in the fresh chain")
+	}
+	if _, c := storeValues(t, s); c.Port != "9000" {
+		t.Errorf("Port = %q, want 9000", c.Port)
+	}
+}
+
+// plainConfig is a Configurer that does not implement Cloner
+type plainConfig struct{ Configurer }
+
+func (p plainConfig) Unwrap() Configurer { return p.Configurer }
+
+func TestStoreRejectsNonClonerLayer(t *testing.T) {
+	inner := plainConfig{NewConfig("", "", WithSource(MapSource{}))}
+	s := NewStore(NewGate(inner, "ENABLED", WithSource(MapSource{})))
+	before := s.Load()
+
+	err := s.Reload()
+	if err == nil || !strings.Contains(err.Error(), "plainConfig") {
+		t.Fatalf("Reload() error = %v, want an error naming plainConfig", err)
+	}
+	if errors.Is(err, ErrReloadNotConfirmed) || s.Load() != before {
+		t.Error("Reload() of a non-Cloner chain swapped or confirmed")
+	}
+}
+
+func TestStoreConfirmFunc(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		approve bool
+		want    string
+		wantErr error
+	}{
+		{name: "approve", approve: true, want: "9001"},
+		{name: "deny", approve: false, want: "9000", wantErr: ErrReloadNotConfirmed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+			base := NewConfig("", "", WithSource(MapSource{}))
+			s := NewStore(NewMessageOfTheDay(NewFileConfig(base, path), "", WithSource(MapSource{})))
+			if err := s.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+
+			var got map[string]Change
+			s.SetConfirmFunc(func(diff map[string]Change) bool {
+				got = diff
+				return tt.approve
+			})
+			writeFile(t, dir, "config.json", `{"PORT": "9001"}`)
+			if err := s.Reload(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reload() error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := map[string]Change{"Port": {Field: "Port", Old: "9000", New: "9001"}}
+			if len(got) != 1 || got["Port"] != want["Port"] {
+				t.Errorf("confirm diff = %v, want %v", got, want)
+			}
+			f := s.Load().(*MessageOfTheDay).Unwrap().(*FileConfig)
+			if port := f.Unwrap().(*Config).GetPort(); port != tt.want {
+				t.Errorf("Port = %q, want %q", port, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreConfirmFuncSkippedWithoutChanges(t *testing.T) {
+	s := NewStore(NewConfig("", "", WithSource(MapSource{"PORT": "9000"})))
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	s.SetConfirmFunc(func(map[string]Change) bool {
+		t.Error("confirm func called for a reload without changes")
+		return false
+	})
+	if err := s.Reload(); err != nil {
+		t.Errorf("Reload() error = %v", err)
+	}
+}
+
+func TestStoreReloadNotifiesAfterSwap(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		content string
+		approve bool
+		want    int
+	}{
+		{name: "approved", content: `{"PORT": "9001"}`, approve: true, want: 1},
+		{name: "denied", content: `{"PORT": "9001"}`, approve: false},
+		{name: "failed", content: `{not json`, approve: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+			f := NewFileConfig(NewConfig("", "", WithSource(MapSource{})), path)
+			s := NewStore(f)
+			if err := s.Reload(); err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+
+			var calls int
+			s.Load().(*FileConfig).OnChange(func(changes []Change) {
+				calls++
+				if _, c := storeValues(t, s); c.Port != "9001" {
+					t.Errorf("callback ran before the swap, Port = %q", c.Port)
+				}
+				if len(changes) != 1 || changes[0] != (Change{Field: "Port", Old: "9000", New: "9001"}) {
+					t.Errorf("changes = %v, want Port 9000 -> 9001", changes)
+				}
+			})
+			s.SetConfirmFunc(func(map[string]Change) bool { return tt.approve })
+
+			writeFile(t, dir, "config.json", tt.content)
+			s.Reload()
+			if calls != tt.want {
+				t.Errorf("callback ran %d times, want %d", calls, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package configdecorator
+
+import "sync"
+
+/*
+#########################################################################
+# Swappable Section - Replaces the entire decorator chain at runtime
+#########################################################################
+*/
+
+// Swappable holds the root of a decorator chain and allows the whole chain to be
+// replaced at runtime, readers go through Current so they always see a complete chain
+type Swappable struct {
+	mu   sync.RWMutex
+	root Configurer
+}
+
+// NewSwappable creates a new Swappable struct holding the root Configurer of a chain
+func NewSwappable(root Configurer) *Swappable {
+	return &Swappable{
+		root: root,
+	}
+}
+
+// Current returns the root Configurer of the chain currently held
+func (s *Swappable) Current() Configurer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.root
+}
+
+// Swap atomically replaces the held chain with newRoot for all readers
+func (s *Swappable) Swap(newRoot Configurer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = newRoot
+}
+
+// Reload reloads the chain currently held and implements the Configurer interface
+func (s *Swappable) Reload() error {
+	return s.Current().Reload()
+}
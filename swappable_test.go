@@ -0,0 +1,82 @@
+package configdecorator
+
+import (
+	"sync"
+	"testing"
+)
+
+// chainDepth counts the layers of the chain below c
+func chainDepth(c Configurer) int {
+	depth := 0
+	for c != nil {
+		depth++
+		u, ok := c.(Unwrapper)
+		if !ok {
+			break
+		}
+		c = u.Unwrap()
+	}
+	return depth
+}
+
+func TestSwappableSwapUnderConcurrentReads(t *testing.T) {
+	src := MapSource{"DB_PORT": "27017", "MOTD": "hello"}
+	two := NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src))
+	three := NewMessageOfTheDay(NewDatabaseConfig(NewConfig("", "", WithSource(src)), "", "", WithSource(src)), "", WithSource(src))
+	for _, chain := range []Configurer{two, three} {
+		if err := chain.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+	}
+	s := NewSwappable(two)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				switch c := s.Current().(type) {
+				case *DatabaseConfig:
+					if depth := chainDepth(c); depth != 2 || c.GetDBPort() != "27017" {
+						t.Errorf("two layer chain depth %d DBPort %q, want a complete chain", depth, c.GetDBPort())
+						return
+					}
+				case *MessageOfTheDay:
+					if depth := chainDepth(c); depth != 3 || c.GetMOTD() != "hello" {
+						t.Errorf("three layer chain depth %d MOTD %q, want a complete chain", depth, c.GetMOTD())
+						return
+					}
+				default:
+					t.Errorf("Current() = %T, want one of the swapped chains", c)
+					return
+				}
+				if err := s.Reload(); err != nil {
+					t.Errorf("Reload() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 100 {
+		if i%2 == 0 {
+			s.Swap(three)
+		} else {
+			s.Swap(two)
+		}
+	}
+	s.Swap(three)
+	close(done)
+	wg.Wait()
+
+	if got := chainDepth(s.Current()); got != 3 {
+		t.Errorf("final chain depth = %d, want the three layer chain", got)
+	}
+}
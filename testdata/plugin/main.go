@@ -0,0 +1,13 @@
+// Command plugin is a config plugin built by the plugin tests, it layers a MessageOfTheDay
+// set from the motd param over the chain it is given
+package main
+
+import "github.com/lkendrickd/configdecorator"
+
+// NewSource builds the plugin's layer and matches configdecorator.PluginSourceFunc
+func NewSource(params map[string]string, next configdecorator.Configurer) (configdecorator.Configurer, error) {
+	src := configdecorator.MapSource{"MOTD": params["motd"]}
+	return configdecorator.NewMessageOfTheDay(next, "", configdecorator.WithSource(src)), nil
+}
+
+func main() {}
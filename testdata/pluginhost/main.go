@@ -0,0 +1,47 @@
+// Command pluginhost loads the config plugin at the path given as its argument through
+// BuildFromEnv and prints the MOTD of the layer it built. The plugin tests run it since a
+// plugin only opens in a binary built the same way as the plugin, which a test binary is not
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lkendrickd/configdecorator"
+)
+
+func main() {
+	if err := run(os.Args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	r := configdecorator.NewRegistry()
+	r.RegisterLayer("base", func(configdecorator.Configurer) (configdecorator.Configurer, error) {
+		return configdecorator.NewConfig("", "", configdecorator.WithSource(configdecorator.MapSource{})), nil
+	})
+	if err := r.RegisterPlugin("remote", path, map[string]string{"motd": "hello from the plugin"}); err != nil {
+		return err
+	}
+
+	os.Setenv("CONFIG_LAYERS", "base,remote")
+	c, err := configdecorator.BuildFromEnv("CONFIG_LAYERS", r)
+	if err != nil {
+		return err
+	}
+	if err := c.Reload(); err != nil {
+		return err
+	}
+
+	motd, ok := c.(*configdecorator.MessageOfTheDay)
+	if !ok {
+		return fmt.Errorf("plugin layer is %T, want *configdecorator.MessageOfTheDay", c)
+	}
+	if _, ok := motd.Unwrap().(*configdecorator.Config); !ok {
+		return fmt.Errorf("plugin layer wraps %T, want the base *configdecorator.Config", motd.Unwrap())
+	}
+	fmt.Println(motd.GetMOTD())
+	return nil
+}
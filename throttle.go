@@ -0,0 +1,105 @@
+package configdecorator
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/*
+#########################################################################
+# Throttle Section - Decorator limiting reloads with a token bucket
+#########################################################################
+*/
+
+// ErrRateLimited is returned by ThrottledConfig when no reload token is available
+var ErrRateLimited = errors.New("reload rate limited")
+
+// ThrottledConfig is a decorator that rate limits reloads with a token bucket, each reload
+// consumes a token and tokens refill at Rate per second up to Burst, so short bursts are
+// allowed while the sustained reload rate is capped
+type ThrottledConfig struct {
+	Configurer
+	Rate  float64
+	Burst float64
+	// Wait makes Reload block until a token is available instead of returning ErrRateLimited
+	Wait bool
+	// Now returns the current time and may be replaced to control the clock in tests
+	Now func() time.Time
+	// Sleep pauses a waiting Reload and is replaced together with Now so a fake clock can advance
+	Sleep func(time.Duration)
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	changeNotifier
+}
+
+// NewThrottledConfig creates a new ThrottledConfig struct that decorates the next Configurer,
+// the bucket starts full with burst tokens and refills at rate tokens per second
+func NewThrottledConfig(next Configurer, rate float64, burst int) *ThrottledConfig {
+	return &ThrottledConfig{
+		Configurer: next,
+		Rate:       rate,
+		Burst:      float64(burst),
+		Now:        time.Now,
+		Sleep:      time.Sleep,
+		tokens:     float64(burst),
+	}
+}
+
+// refill adds the tokens earned since the last refill, the caller must hold the lock
+func (t *ThrottledConfig) refill() {
+	now := t.Now()
+	if !t.last.IsZero() {
+		t.tokens = min(t.Burst, t.tokens+now.Sub(t.last).Seconds()*t.Rate)
+	}
+	t.last = now
+}
+
+// AvailableTokens returns the number of reload tokens currently available
+func (t *ThrottledConfig) AvailableTokens() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refill()
+	return t.tokens
+}
+
+// Reload reloads the wrapped configuration if a token is available and implements the Configurer interface
+func (t *ThrottledConfig) Reload() error {
+	before := t.hooks.before(t)
+	err := t.reload()
+	t.hooks.notify(before, t, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (t *ThrottledConfig) reload() error {
+	for {
+		t.mu.Lock()
+		t.refill()
+		if t.tokens >= 1 {
+			t.tokens--
+			t.mu.Unlock()
+			return t.Configurer.Reload()
+		}
+		if !t.Wait || t.Rate <= 0 {
+			t.mu.Unlock()
+			return ErrRateLimited
+		}
+
+		// Sleep until the next token is due and try again
+		wait := time.Duration((1 - t.tokens) / t.Rate * float64(time.Second))
+		sleep := t.Sleep
+		t.mu.Unlock()
+		if sleep == nil {
+			sleep = time.Sleep
+		}
+		sleep(wait)
+	}
+}
+
+// Unwrap returns the Configurer wrapped by the ThrottledConfig decorator
+func (t *ThrottledConfig) Unwrap() Configurer {
+	return t.Configurer
+}
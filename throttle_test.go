@@ -0,0 +1,88 @@
+package configdecorator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for the time based decorators
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) { c.now = c.now.Add(d) }
+
+// countingConfig is a base Configurer counting its reloads
+type countingConfig struct {
+	reloads int
+}
+
+func (c *countingConfig) Reload() error {
+	c.reloads++
+	return nil
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestThrottledConfigBurst(t *testing.T) {
+	clock := newFakeClock()
+	inner := &countingConfig{}
+	th := NewThrottledConfig(inner, 1, 3)
+	th.Now = clock.Now
+
+	for i := range 3 {
+		if err := th.Reload(); err != nil {
+			t.Fatalf("Reload() %d error = %v, want the burst to be allowed", i, err)
+		}
+	}
+	if err := th.Reload(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Reload() after the burst error = %v, want ErrRateLimited", err)
+	}
+	if inner.reloads != 3 {
+		t.Errorf("inner reloads = %d, want 3", inner.reloads)
+	}
+}
+
+func TestThrottledConfigSteadyState(t *testing.T) {
+	clock := newFakeClock()
+	inner := &countingConfig{}
+	th := NewThrottledConfig(inner, 2, 1)
+	th.Now = clock.Now
+
+	allowed := 0
+	for range 40 {
+		if th.Reload() == nil {
+			allowed++
+		}
+		clock.Sleep(100 * time.Millisecond)
+	}
+	// One token every half second over the 4 seconds of attempts
+	if allowed != 8 {
+		t.Errorf("allowed reloads = %d, want 8", allowed)
+	}
+}
+
+func TestThrottledConfigWaitUsesInjectedClock(t *testing.T) {
+	clock := newFakeClock()
+	inner := &countingConfig{}
+	th := NewThrottledConfig(inner, 4, 1)
+	th.Now, th.Sleep, th.Wait = clock.Now, clock.Sleep, true
+	start := clock.now
+
+	for range 3 {
+		if err := th.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+	}
+	if inner.reloads != 3 {
+		t.Errorf("inner reloads = %d, want 3", inner.reloads)
+	}
+	if elapsed := clock.now.Sub(start); elapsed != 500*time.Millisecond {
+		t.Errorf("fake clock advanced %v, want 500ms for two waits of a quarter second", elapsed)
+	}
+}
@@ -0,0 +1,33 @@
+package configdecorator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+#########################################################################
+# Validate Command Section - Helper for a "config validate" CLI command
+#########################################################################
+*/
+
+// RunValidateCommand reloads c, writes the effective configuration and any reload errors to w
+// and returns the exit code for a "config validate" style command, 0 when the configuration
+// loaded cleanly and 1 otherwise
+func RunValidateCommand(c Configurer, w io.Writer) int {
+	err := c.Reload()
+
+	fmt.Fprintln(w, "Effective configuration:")
+	for _, field := range snapshot(c) {
+		fmt.Fprintf(w, "  %s: %s\n", field.Field, field.Value)
+	}
+
+	if err != nil {
+		// Joined errors put each layer's failure on its own line, indent every one of them
+		fmt.Fprintf(w, "Configuration is invalid:\n  %s\n", strings.ReplaceAll(err.Error(), "\n", "\n  "))
+		return 1
+	}
+	fmt.Fprintln(w, "Configuration is valid")
+	return 0
+}
@@ -0,0 +1,47 @@
+package configdecorator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunValidateCommandValid(t *testing.T) {
+	src := MapSource{"PORT": "9000", "MOTD": "hi"}
+	chain := NewMessageOfTheDay(NewConfig("", "", WithSource(src)), "", WithSource(src))
+
+	var out bytes.Buffer
+	if code := RunValidateCommand(chain, &out); code != 0 {
+		t.Errorf("RunValidateCommand() = %d, want 0", code)
+	}
+	want := "Effective configuration:\n" +
+		"  MOTD: hi\n" +
+		"  Address: http://localhost\n" +
+		"  Port: 9000\n" +
+		"Configuration is valid\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRunValidateCommandInvalid(t *testing.T) {
+	src := MapSource{"MAX_WORKERS": "many", "ADDRESSES": "nope"}
+	chain := NewAddressesConfig(NewResourceAwareConfig(NewConfig("", "", WithSource(src)), WithSource(src)), WithSource(src))
+
+	var out bytes.Buffer
+	if code := RunValidateCommand(chain, &out); code != 1 {
+		t.Errorf("RunValidateCommand() = %d, want 1", code)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Configuration is invalid:\n  resource aware config: ",
+		"\n  weighted addresses: invalid weighted address",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output =\n%s\nwant it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Configuration is valid") {
+		t.Errorf("output =\n%s\nwant no valid line", got)
+	}
+}
@@ -0,0 +1,127 @@
+package configdecorator
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+)
+
+/*
+#########################################################################
+# Versioned Config Section - Decorator enforcing a config schema version
+#########################################################################
+*/
+
+// VersionedConfig is a decorator that refuses to load configuration written for a different schema
+// version than the application expects. Read Version through GetVersion when the config may be reloaded concurrently
+type VersionedConfig struct {
+	Configurer
+	ExpectedVersion int
+	Version         int
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	source Source
+	mu     sync.RWMutex
+	changeNotifier
+	reloadCounter
+}
+
+// NewVersionedConfig creates a new VersionedConfig struct that decorates the next Configurer
+// and expects the CONFIG_VERSION environment variable to match the expected version
+func NewVersionedConfig(next Configurer, expected int, opts ...Option) *VersionedConfig {
+	o := applyOptions(opts)
+	return &VersionedConfig{
+		Configurer:      next,
+		ExpectedVersion: expected,
+		source:          o.source,
+	}
+}
+
+// Reload checks the configuration schema version and reloads the wrapped configuration,
+// it implements the Configurer interface. Unlike the other decorators a version error stops
+// the wrapped configuration from being reloaded at all, since it was written for another version
+func (v *VersionedConfig) Reload() error {
+	before := v.hooks.before(v)
+	err := v.reload()
+	v.hooks.notify(before, v, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (v *VersionedConfig) reload() error {
+	logf(v.Logger, "Checking config version")
+	v.reloaded()
+
+	version, err := v.checkVersion()
+	if err != nil {
+		return layerError(v, err)
+	}
+	v.mu.Lock()
+	v.Version = version
+	v.mu.Unlock()
+
+	// Reload the wrapped configuration only once the version is known to match
+	return v.Configurer.Reload()
+}
+
+// GetVersion returns the Version accepted by the last reload and is safe to call while the config is reloading
+func (v *VersionedConfig) GetVersion() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.Version
+}
+
+// checkVersion loads CONFIG_VERSION from the source and compares it with the expected version
+func (v *VersionedConfig) checkVersion() (int, error) {
+	v.mu.RLock()
+	source := v.source
+	v.mu.RUnlock()
+
+	// Load the value from the source, unlike the other fields a version is required
+	raw, ok := sourceOrEnv(source).Get("CONFIG_VERSION")
+	if !ok {
+		return 0, fmt.Errorf("config version not set: expected CONFIG_VERSION=%d", v.ExpectedVersion)
+	}
+
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CONFIG_VERSION %q: %w", raw, err)
+	}
+	if version != v.ExpectedVersion {
+		return 0, fmt.Errorf("config version mismatch: expected %d, got %d", v.ExpectedVersion, version)
+	}
+	return version, nil
+}
+
+// layerName returns the name the VersionedConfig reports its errors under
+func (v *VersionedConfig) layerName() string {
+	return "config version"
+}
+
+// Clone returns a copy of the VersionedConfig and the chain it wraps and implements the Cloner interface
+func (v *VersionedConfig) Clone() Configurer {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	clone := &VersionedConfig{
+		Configurer:      cloneConfigurer(v.Configurer),
+		ExpectedVersion: v.ExpectedVersion,
+		Version:         v.Version,
+		Logger:          v.Logger,
+		source:          v.source,
+	}
+	v.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// replaceSource swaps the Source for the one returned by replace and implements the sourcedLayer interface
+func (v *VersionedConfig) replaceSource(replace func(Source) Source) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.source = replace(v.source)
+}
+
+// Unwrap returns the Configurer wrapped by the VersionedConfig decorator
+func (v *VersionedConfig) Unwrap() Configurer {
+	return v.Configurer
+}
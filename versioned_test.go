@@ -0,0 +1,92 @@
+package configdecorator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionedConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		src         MapSource
+		wantErr     bool
+		wantReloads int
+	}{
+		{name: "match", src: MapSource{"CONFIG_VERSION": "2"}, wantReloads: 1},
+		{name: "mismatch", src: MapSource{"CONFIG_VERSION": "1"}, wantErr: true},
+		{name: "unset", src: MapSource{}, wantErr: true},
+		{name: "invalid", src: MapSource{"CONFIG_VERSION": "two"}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingConfig{}
+			v := NewVersionedConfig(inner, 2, WithSource(tt.src))
+
+			err := v.Reload()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var layerErr *LayerError
+			if tt.wantErr && (!errors.As(err, &layerErr) || layerErr.Layer != "config version") {
+				t.Errorf("Reload() error = %v, want a config version LayerError", err)
+			}
+			if inner.reloads != tt.wantReloads {
+				t.Errorf("inner reloads = %d, want %d", inner.reloads, tt.wantReloads)
+			}
+			if !tt.wantErr && v.GetVersion() != 2 {
+				t.Errorf("GetVersion() = %d, want 2", v.GetVersion())
+			}
+		})
+	}
+}
+
+func TestVersionedConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"config_version": 2}`)
+	inner := &countingConfig{}
+	v := NewVersionedConfig(inner, 2, WithSource(MapSource{}))
+	f := NewFileConfig(v, path)
+
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if v.Version != 2 || inner.reloads != 1 {
+		t.Errorf("Version = %d with %d inner reloads, want 2 read from the file", v.Version, inner.reloads)
+	}
+
+	writeFile(t, dir, "config.json", `{"config_version": 3}`)
+	if err := f.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the file's version mismatch")
+	}
+}
+
+func TestVersionedConfigMismatchKeepsVersion(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"config_version": 2, "port": "9000"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	v := NewVersionedConfig(base, 2, WithSource(MapSource{}))
+	f := NewFileConfig(v, path)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	// A file written for another schema version must not reach the base config
+	writeFile(t, dir, "config.json", `{"config_version": 1, "port": "9001"}`)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := f.Reload(); err == nil {
+			t.Error("Reload() error = nil, want the version mismatch")
+		}
+	}()
+	if got := v.GetVersion(); got != 2 {
+		t.Errorf("GetVersion() = %d during the reload, want 2", got)
+	}
+	<-done
+
+	if got := v.GetVersion(); got != 2 {
+		t.Errorf("GetVersion() = %d, want the last accepted version 2", got)
+	}
+	if got := base.GetPort(); got != "9000" {
+		t.Errorf("Port = %q, want the base config left at 9000", got)
+	}
+}
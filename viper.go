@@ -0,0 +1,136 @@
+package configdecorator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+#########################################################################
+# Viper Section - Decorator bridging an existing viper setup
+#########################################################################
+*/
+
+// Viper is the subset of *viper.Viper used by ViperConfig, a *viper.Viper satisfies it
+// directly so this package does not need to depend on viper
+type Viper interface {
+	ReadInConfig() error
+	IsSet(key string) bool
+	GetString(key string) string
+}
+
+// ViperConfig is a decorator that provides the values of a viper instance to the chain it wraps,
+// layered under the wrapped layers' sources as FileConfig does. Viper keys are the lower case
+// environment variable names, e.g. "db_port"
+type ViperConfig struct {
+	Configurer
+	Viper Viper
+	// Logger receives debug messages when set, a nil Logger keeps the config silent
+	Logger *log.Logger
+	// values reads the keys set in Viper for the wrapped layers' Sources once layered has run
+	values  *viperValues
+	layered sync.Once
+	changeNotifier
+	reloadCounter
+}
+
+// viperValues is the Source a ViperConfig layers under the wrapped layers, it reads the keys set in its Viper
+type viperValues struct {
+	config *ViperConfig
+	// cleared hides the viper keys from the wrapped layers until the next reload
+	cleared atomic.Bool
+}
+
+// Get looks up the lower case key in viper and implements the Source interface
+func (v *viperValues) Get(key string) (string, bool) {
+	if v.cleared.Load() {
+		return "", false
+	}
+	name := strings.ToLower(key)
+	if !v.config.Viper.IsSet(name) {
+		return "", false
+	}
+	return v.config.Viper.GetString(name), true
+}
+
+// NewViperConfig creates a new ViperConfig struct that decorates the next Configurer with values read by v
+func NewViperConfig(next Configurer, v Viper) *ViperConfig {
+	config := &ViperConfig{
+		Configurer: next,
+		Viper:      v,
+	}
+	config.layerValues()
+	return config
+}
+
+// layerValues layers the viper values into the Sources of the wrapped layers the first time it is called
+func (v *ViperConfig) layerValues() {
+	v.layered.Do(func() {
+		v.values = &viperValues{config: v}
+		layerSource(v.Configurer, v.values)
+	})
+}
+
+// Reload rereads the viper config and then reloads the wrapped configuration with every key
+// set in viper layered under their sources, it implements the Configurer interface
+func (v *ViperConfig) Reload() error {
+	before := v.hooks.before(v)
+	err := v.reload()
+	v.hooks.notify(before, v, err)
+	return err
+}
+
+// reload loads this layer and the chain below it, Reload wraps it with the change notifications
+func (v *ViperConfig) reload() error {
+	logf(v.Logger, "Reloading viper config")
+	v.reloaded()
+
+	// Read the viper config first so the wrapped layers bind its values as they reload
+	v.layerValues()
+	v.values.cleared.Store(false)
+	var readErr error
+	if err := v.Viper.ReadInConfig(); err != nil {
+		readErr = fmt.Errorf("reading viper config: %w", err)
+	}
+
+	// Reload the wrapped configuration
+	err := v.Configurer.Reload()
+	return errors.Join(err, layerError(v, readErr))
+}
+
+// Clone returns a copy of the ViperConfig and the chain it wraps and implements the Cloner interface,
+// the clone shares the Viper instance since viper offers no way to copy one
+func (v *ViperConfig) Clone() Configurer {
+	v.layerValues()
+	clone := &ViperConfig{
+		Configurer: cloneConfigurer(v.Configurer),
+		Viper:      v.Viper,
+		Logger:     v.Logger,
+	}
+	clone.values = &viperValues{config: clone}
+	clone.values.cleared.Store(v.values.cleared.Load())
+	relayerSource(clone.Configurer, v.values, clone.values)
+	clone.layered.Do(func() {})
+	v.hooks.copyTo(&clone.hooks)
+	return clone
+}
+
+// unlayer hides the viper keys from the wrapped layers and implements the valueDecorator interface
+func (v *ViperConfig) unlayer() {
+	v.layerValues()
+	v.values.cleared.Store(true)
+}
+
+// layerName returns the name the ViperConfig reports its errors under
+func (v *ViperConfig) layerName() string {
+	return "viper config"
+}
+
+// Unwrap returns the Configurer wrapped by the ViperConfig decorator
+func (v *ViperConfig) Unwrap() Configurer {
+	return v.Configurer
+}
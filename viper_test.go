@@ -0,0 +1,87 @@
+package configdecorator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeViper is a Viper whose ReadInConfig loads the pending values, or fails with err
+type fakeViper struct {
+	pending map[string]string
+	values  map[string]string
+	err     error
+}
+
+func (f *fakeViper) ReadInConfig() error {
+	if f.err != nil {
+		return f.err
+	}
+	f.values = f.pending
+	return nil
+}
+
+func (f *fakeViper) IsSet(key string) bool {
+	_, ok := f.values[key]
+	return ok
+}
+
+func (f *fakeViper) GetString(key string) string {
+	return f.values[key]
+}
+
+func TestViperConfigLayersViperValues(t *testing.T) {
+	fake := &fakeViper{pending: map[string]string{"db_address": "http://viper", "db_port": "1", "port": "9001"}}
+	base := NewConfig("", "", WithSource(MapSource{"PORT": "9000"}))
+	db := NewDatabaseConfig(base, "", "", WithSource(MapSource{}))
+	v := NewViperConfig(db, fake)
+
+	var changes []Change
+	v.OnChange(func(c []Change) { changes = append(changes, c...) })
+	if err := v.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := db.GetDBAddress(); got != "http://viper" {
+		t.Errorf("DBAddress = %q, want the viper value", got)
+	}
+	if got := base.GetPort(); got != "9000" {
+		t.Errorf("Port = %q, want the layer's own source to win over viper", got)
+	}
+	if got := base.GetAddress(); got != "http://localhost" {
+		t.Errorf("Address = %q, want the default for a key set nowhere", got)
+	}
+
+	// A later viper change reaches the chain and its change hooks
+	fake.pending = map[string]string{"db_address": "http://viper2"}
+	if err := v.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	want := []Change{
+		{Field: "DBAddress", Old: "http://viper", New: "http://viper2"},
+		{Field: "DBPort", Old: "1", New: "37017"},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestViperConfigReadError(t *testing.T) {
+	fake := &fakeViper{pending: map[string]string{"db_port": "1"}}
+	db := NewDatabaseConfig(NewConfig("", "", WithSource(MapSource{})), "", "", WithSource(MapSource{}))
+	v := NewViperConfig(db, fake)
+	if err := v.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	errRead := errors.New("config file vanished")
+	fake.err = errRead
+	err := v.Reload()
+	var layerErr *LayerError
+	if !errors.Is(err, errRead) || !errors.As(err, &layerErr) || layerErr.Layer != "viper config" {
+		t.Fatalf("Reload() error = %v, want the read error reported by the viper config layer", err)
+	}
+	if got := db.GetDBPort(); got != "1" {
+		t.Errorf("DBPort = %q, want the values viper still holds", got)
+	}
+}
@@ -0,0 +1,195 @@
+package configdecorator
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+/*
+#########################################################################
+# Signal Watch Section - Reloads when the process receives a signal
+#########################################################################
+*/
+
+// Watch reloads c every time the process receives one of sig, SIGHUP when none are given,
+// until ctx is cancelled. The result of every reload, nil on success, is sent on the returned
+// channel so a failed reload is never mistaken for success, and the channel is closed once the
+// watch stops. The signal handler is registered before Watch returns and removed on exit
+func Watch(ctx context.Context, c Configurer, sig ...os.Signal) <-chan error {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGHUP}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig...)
+
+	results := make(chan error)
+	go func() {
+		defer close(results)
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+			}
+
+			select {
+			case results <- c.Reload():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+/*
+#########################################################################
+# Env Watch Section - Reloads when specific environment variables change
+#########################################################################
+*/
+
+// lookupEnvVars returns the current value of each named environment variable,
+// unset variables are left out so that unsetting a variable counts as a change
+func lookupEnvVars(vars []string) map[string]string {
+	values := make(map[string]string, len(vars))
+	for _, name := range vars {
+		if v, ok := os.LookupEnv(name); ok {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+// WatchEnvVars polls the named environment variables every interval and reloads c whenever
+// any of them differs from its last seen value, unrelated variables are ignored. The result of
+// every triggered reload, nil on success, is sent on the returned channel which is closed once
+// ctx is cancelled
+func WatchEnvVars(ctx context.Context, c Configurer, interval time.Duration, vars ...string) <-chan error {
+	results := make(chan error)
+
+	go func() {
+		defer close(results)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastSeen := lookupEnvVars(vars)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := lookupEnvVars(vars)
+			if maps.Equal(current, lastSeen) {
+				continue
+			}
+			lastSeen = current
+
+			select {
+			case results <- c.Reload():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+/*
+#########################################################################
+# Jittered Watch Section - Reloads on an interval spread across a fleet
+#########################################################################
+*/
+
+// jitterOptions holds the optional settings of WatchWithJitter
+type jitterOptions struct {
+	rand  *rand.Rand
+	after func(time.Duration) <-chan time.Time
+}
+
+// JitterOption configures optional settings of WatchWithJitter
+type JitterOption func(*jitterOptions)
+
+// WithJitterRand sets the random source the jitter is drawn from, seed it for a repeatable sequence of intervals
+func WithJitterRand(r *rand.Rand) JitterOption {
+	return func(o *jitterOptions) {
+		o.rand = r
+	}
+}
+
+// WithJitterAfter sets the clock WatchWithJitter waits on, after returns a channel that receives
+// once d has passed like time.After and may be replaced to drive the watch from a fake clock in tests
+func WithJitterAfter(after func(d time.Duration) <-chan time.Time) JitterOption {
+	return func(o *jitterOptions) {
+		o.after = after
+	}
+}
+
+// jitteredInterval returns base shifted by a random amount in [-jitter, jitter] drawn from r, the
+// global source when nil. A jitter larger than base is capped at base so the interval is never negative
+func jitteredInterval(r *rand.Rand, base, jitter time.Duration) time.Duration {
+	jitter = min(jitter, base)
+	if jitter <= 0 {
+		return base
+	}
+	if r == nil {
+		return base - jitter + rand.N(2*jitter+1)
+	}
+	return base - jitter + time.Duration(r.Int64N(int64(2*jitter+1)))
+}
+
+// WatchWithJitter reloads c repeatedly until ctx is cancelled, waiting base plus or minus a random
+// amount up to jitter before each reload so replicas sharing a source do not reload in lockstep.
+// The result of every reload, nil on success, is sent on the returned channel which is closed
+// once ctx is cancelled. Pass WithJitterRand and WithJitterAfter to make the intervals deterministic
+func WatchWithJitter(ctx context.Context, c Configurer, base, jitter time.Duration, opts ...JitterOption) <-chan error {
+	var o jitterOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	results := make(chan error)
+
+	go func() {
+		defer close(results)
+
+		after := o.after
+		if after == nil {
+			// Reuse a single timer rather than leaving one behind per interval
+			timer := time.NewTimer(time.Hour)
+			timer.Stop()
+			defer timer.Stop()
+			after = func(d time.Duration) <-chan time.Time {
+				timer.Reset(d)
+				return timer.C
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-after(jitteredInterval(o.rand, base, jitter)):
+			}
+
+			select {
+			case results <- c.Reload():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
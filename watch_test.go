@@ -0,0 +1,108 @@
+package configdecorator
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+)
+
+// jitterIntervals runs WatchWithJitter over a fake clock seeded with seed and returns the first n intervals it waited
+func jitterIntervals(t *testing.T, seed uint64, base, jitter time.Duration, n int) []time.Duration {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var intervals []time.Duration
+	fired := make(chan time.Time, 1)
+	after := func(d time.Duration) <-chan time.Time {
+		intervals = append(intervals, d)
+		fired <- time.Time{}
+		return fired
+	}
+
+	results := WatchWithJitter(ctx, &countingConfig{}, base, jitter,
+		WithJitterRand(rand.New(rand.NewPCG(seed, seed))), WithJitterAfter(after))
+	for range n {
+		if err := <-results; err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+	}
+	cancel()
+	for range results {
+	}
+	return intervals[:n]
+}
+
+func TestWatchWithJitterIntervalRange(t *testing.T) {
+	base, jitter := time.Minute, 10*time.Second
+	intervals := jitterIntervals(t, 1, base, jitter, 100)
+
+	distinct := make(map[time.Duration]bool)
+	for _, d := range intervals {
+		if d < base-jitter || d > base+jitter {
+			t.Errorf("interval %v outside [%v, %v]", d, base-jitter, base+jitter)
+		}
+		distinct[d] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("intervals = %v, want them spread by the jitter", intervals)
+	}
+
+	again := jitterIntervals(t, 1, base, jitter, 100)
+	for i := range intervals {
+		if intervals[i] != again[i] {
+			t.Fatalf("interval %d = %v then %v, want the same sequence for the same seed", i, intervals[i], again[i])
+		}
+	}
+}
+
+func TestJitteredIntervalCapsJitterAtBase(t *testing.T) {
+	r := rand.New(rand.NewPCG(2, 2))
+	for range 100 {
+		if d := jitteredInterval(r, time.Second, time.Minute); d < 0 || d > 2*time.Second {
+			t.Fatalf("jitteredInterval() = %v, want it within [0, 2s]", d)
+		}
+	}
+	if d := jitteredInterval(r, time.Second, 0); d != time.Second {
+		t.Errorf("jitteredInterval() without jitter = %v, want the base", d)
+	}
+}
+
+func TestWatchEnvVarsIgnoresUnwatchedVars(t *testing.T) {
+	t.Setenv("WATCHED_VAR", "one")
+	t.Setenv("UNWATCHED_VAR", "one")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inner := &signalConfig{reloaded: make(chan struct{}, 1)}
+	results := WatchEnvVars(ctx, inner, 5*time.Millisecond, "WATCHED_VAR")
+
+	os.Setenv("UNWATCHED_VAR", "two")
+	select {
+	case <-results:
+		t.Fatal("WatchEnvVars reloaded for an unwatched variable")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	os.Setenv("WATCHED_VAR", "two")
+	select {
+	case err := <-results:
+		if err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchEnvVars did not reload after the watched variable changed")
+	}
+	<-inner.reloaded
+
+	// Unsetting a watched variable counts as a change too
+	os.Unsetenv("WATCHED_VAR")
+	select {
+	case <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchEnvVars did not reload after the watched variable was unset")
+	}
+	<-inner.reloaded
+}
@@ -0,0 +1,59 @@
+//go:build linux || darwin || freebsd
+
+package configdecorator
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchReloadsOnSIGHUP(t *testing.T) {
+	// Keep a handler installed for the whole test so a SIGHUP arriving after Watch stops does not kill the process
+	guard := make(chan os.Signal, 4)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inner := &countingConfig{}
+	results := Watch(ctx, inner)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-results:
+		if err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not reload on SIGHUP")
+	}
+
+	cancel()
+	for range results {
+	}
+
+	// After the watch stopped its handler is gone, so another SIGHUP reaches only the guard
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(5 * time.Second)
+	for drained := false; !drained; {
+		select {
+		case <-guard:
+			if len(guard) == 0 {
+				drained = true
+			}
+		case <-deadline:
+			t.Fatal("guard did not receive the SIGHUP")
+		}
+	}
+	if inner.reloads != 1 {
+		t.Errorf("reloads = %d, want 1, the watch kept reloading after it stopped", inner.reloads)
+	}
+}
@@ -0,0 +1,174 @@
+package configdecorator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+#########################################################################
+# Change Webhook Section - Posts reload changes to an external endpoint
+#########################################################################
+*/
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of a webhook body, prefixed with "sha256="
+const SignatureHeader = "X-Config-Signature"
+
+// DefaultWebhookTimeout bounds a single webhook post when ChangeWebhook.Timeout is not set
+const DefaultWebhookTimeout = 5 * time.Second
+
+// webhookQueueSize is how many reloads' changes may wait to be posted before new ones are dropped
+const webhookQueueSize = 16
+
+// ChangeWebhook posts the changes of every reload as a JSON array to URL, register its Notify
+// method with OnChange on the outermost layer. When Secret is set each request is signed in the
+// SignatureHeader so the receiver can verify it came from this service. Posts are sent from a
+// background goroutine so a slow endpoint never holds up a reload, call Close to flush and stop it
+type ChangeWebhook struct {
+	URL    string
+	Client *http.Client
+	Secret []byte
+	// Attempts is how many times a failed post is tried before it is given up on
+	Attempts   int
+	RetryDelay time.Duration
+	// Timeout bounds each attempt, DefaultWebhookTimeout when not set
+	Timeout time.Duration
+	// Logger receives debug messages and failed posts when set, a nil Logger keeps the webhook silent
+	Logger *log.Logger
+	start  sync.Once
+	queue  chan []byte
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewChangeWebhook creates a new ChangeWebhook struct posting to url with client, a client
+// timing out after DefaultWebhookTimeout when nil
+func NewChangeWebhook(url string, client *http.Client, secret []byte) *ChangeWebhook {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return &ChangeWebhook{
+		URL:        url,
+		Client:     client,
+		Secret:     secret,
+		Attempts:   3,
+		RetryDelay: 500 * time.Millisecond,
+		Timeout:    DefaultWebhookTimeout,
+	}
+}
+
+// Notify queues changes to be posted to the webhook and returns without waiting for the post,
+// so it never holds up or fails the reload that made the changes. Changes arriving while the
+// queue is full or after Close are dropped and only logged
+func (w *ChangeWebhook) Notify(changes []Change) {
+	body, err := json.Marshal(changes)
+	if err != nil {
+		logf(w.Logger, "Encoding config change webhook: %v", err)
+		return
+	}
+
+	w.start.Do(w.run)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		logf(w.Logger, "Dropping config change webhook after Close")
+		return
+	}
+	select {
+	case w.queue <- body:
+	default:
+		logf(w.Logger, "Dropping config change webhook, %d posts are already queued", webhookQueueSize)
+	}
+}
+
+// Close stops accepting changes and waits for the queued posts to be sent or given up on
+func (w *ChangeWebhook) Close() {
+	w.start.Do(w.run)
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+// run starts the goroutine posting the queued changes
+func (w *ChangeWebhook) run() {
+	w.queue = make(chan []byte, webhookQueueSize)
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		for body := range w.queue {
+			w.deliver(body)
+		}
+	}()
+}
+
+// deliver posts body and retries briefly on failure, a post that is given up on is only logged
+func (w *ChangeWebhook) deliver(body []byte) {
+	for attempt := 1; ; attempt++ {
+		err := w.post(body)
+		if err == nil {
+			return
+		}
+		if attempt >= w.Attempts {
+			logf(w.Logger, "Giving up on config change webhook after %d attempts: %v", attempt, err)
+			return
+		}
+		time.Sleep(w.RetryDelay)
+	}
+}
+
+// post sends a single signed webhook request bounded by Timeout, any status other than 2xx is an error
+func (w *ChangeWebhook) post(body []byte) error {
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.Secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+SignWebhookBody(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhookBody returns the hex encoded HMAC-SHA256 of body with secret, as sent in the SignatureHeader
+func SignWebhookBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,124 @@
+package configdecorator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChangeWebhookSignsAndRetries(t *testing.T) {
+	secret := []byte("s3cret")
+	var calls atomic.Int32
+	received := make(chan []Change, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), "sha256="+SignWebhookBody(secret, body); got != want {
+			t.Errorf("%s = %q, want %q", SignatureHeader, got, want)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var changes []Change
+		if err := json.Unmarshal(body, &changes); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		received <- changes
+	}))
+	defer server.Close()
+
+	w := NewChangeWebhook(server.URL, server.Client(), secret)
+	w.RetryDelay = time.Millisecond
+	w.Notify([]Change{{Field: "Port", Old: "8081", New: "9000"}})
+	w.Close()
+
+	select {
+	case changes := <-received:
+		if len(changes) != 1 || changes[0] != (Change{Field: "Port", Old: "8081", New: "9000"}) {
+			t.Errorf("webhook body = %v, want the Port change", changes)
+		}
+	default:
+		t.Fatal("webhook never received the changes")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("webhook calls = %d, want a retry after the failed post", got)
+	}
+}
+
+func TestChangeWebhookDoesNotBlockOnHangingEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	w := NewChangeWebhook(server.URL, server.Client(), nil)
+	w.Attempts = 1
+	w.Timeout = 500 * time.Millisecond
+
+	start := time.Now()
+	w.Notify([]Change{{Field: "Port", Old: "8081", New: "9000"}})
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Notify() took %v, want it to return without waiting for the post", elapsed)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		w.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not return, want the attempt to time out")
+	}
+}
+
+func TestChangeWebhookSkipsFailedReload(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.json", `{"PORT": "9000"}`)
+	base := NewConfig("", "", WithSource(MapSource{}))
+	f := NewFileConfig(NewResourceAwareConfig(base, WithSource(MapSource{})), path)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	w := NewChangeWebhook(server.URL, server.Client(), nil)
+	var got []Change
+	f.OnChange(func(changes []Change) {
+		got = changes
+		w.Notify(changes)
+	})
+
+	// One layer failing fails the whole reload, even though the Port of the base config changed
+	writeFile(t, dir, "config.json", `{"PORT": "9001", "MAX_WORKERS": "many"}`)
+	if err := f.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the MAX_WORKERS parse error")
+	}
+	w.Close()
+	if n := calls.Load(); n != 0 || got != nil {
+		t.Errorf("webhook received %d posts and changes %v for a failed reload, want none", n, got)
+	}
+
+	writeFile(t, dir, "config.json", `{"PORT": "9001"}`)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != (Change{Field: "Port", Old: "9000", New: "9001"}) {
+		t.Errorf("changes = %v, want the Port change since the last successful reload", got)
+	}
+}